@@ -2,42 +2,172 @@ package main
 
 import (
 	"ResumeBuilder/internal/agent"
+	"ResumeBuilder/internal/cache"
+	"ResumeBuilder/internal/config"
 	"ResumeBuilder/internal/controller"
 	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/job"
+	"ResumeBuilder/internal/migration"
 	"ResumeBuilder/internal/route"
 	"ResumeBuilder/internal/service"
+	"ResumeBuilder/internal/validation"
+	"context"
+	"errors"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 
-	"github.com/joho/godotenv"
+	"github.com/redis/go-redis/v9"
 )
 
 func main() {
-	// 加载.env文件（如果存在）
-	if err := godotenv.Load(); err != nil {
-		log.Println("警告：未找到.env文件，将使用系统环境变量")
-	} else {
-		log.Println("✅ 成功加载.env文件")
+	// migrate 子命令：管理数据库结构版本后退出，只需要数据库配置
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		dbCfg, err := config.LoadDB()
+		if err != nil {
+			log.Fatalf("❌ 错误：数据库配置无效，请检查.env、config.yaml或系统环境变量：\n%v", err)
+		}
+		if err := runMigrate(*dbCfg, os.Args[2:]); err != nil {
+			log.Fatalf("❌ 错误：%v", err)
+		}
+		return
+	}
+
+	// 加载配置（默认值 < config.yaml < .env / 系统环境变量），所有错误一次性报告
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("❌ 错误：配置无效，请检查.env、config.yaml或系统环境变量：\n%v", err)
 	}
+	log.Println("✅ 配置加载成功")
 
-	// 检查必要的环境变量
-	apiKey := os.Getenv("apiKey")
-	if apiKey == "" {
-		log.Fatal("❌ 错误：apiKey环境变量未设置，请在.env文件中配置或设置系统环境变量")
+	// 根据配置选择大模型提供方（ark / openai / fake），默认使用火山方舟
+	provider, err := agent.NewProvider(cfg.AI)
+	if err != nil {
+		log.Fatal("❌ 错误：AI提供方初始化失败，请检查AI_PROVIDER与apiKey配置：", err)
 	}
-	log.Println("✅ API Key已配置")
+	log.Printf("✅ AI提供方已配置: %s（简历解析模型: %s，GitHub分析模型: %s）\n",
+		provider.Name(), cfg.AI.Resume.Model, cfg.AI.GitHub.Model)
 
-	// 初始化服务
-	db := dao.NewResumeDAO()
-	aiAgent := agent.NewAIAgent()
-	resumeService := service.NewResumeService(db, aiAgent)
+	// 初始化服务：只有 Redis 缓存和任务队列需要 Redis
+	var redisClient *redis.Client
+	if cfg.NeedsRedis() {
+		redisClient = dao.NewRedisClient(cfg.Redis)
+	}
+	store, err := cache.New(cfg.Cache, redisClient)
+	if err != nil {
+		log.Fatal("❌ 错误：缓存初始化失败：", err)
+	}
+	log.Printf("✅ 简历缓存: %s\n", cfg.Cache.Backend)
+	db, err := dao.NewResumeDAO(cfg.DB, store, cfg.Cache)
+	if err != nil {
+		if errors.Is(err, migration.ErrSchemaBehind) {
+			log.Fatal("❌ 错误：", err, "（或设置 DB_AUTO_MIGRATE=true 在启动时自动执行）")
+		}
+		log.Fatal("❌ 错误：存储层初始化失败：", err)
+	}
+	log.Printf("✅ 简历存储: %s\n", cfg.DB.Driver)
+	validator := validation.New(cfg.Validation)
+	aiAgent := agent.NewAIAgent(provider, cfg.AI, cfg.GitHub, validator)
+	resumeService := service.NewResumeService(db, aiAgent, validator)
 	resumeController := controller.NewResumeController(resumeService)
-	r := route.Run(resumeController)
+
+	// 异步任务队列与 Redis 缓存共用 Redis；未启用时不注册任务接口
+	var queue *job.Queue
+	var jobController *controller.JobController
+	if cfg.Jobs.Enabled {
+		queue = job.NewQueue(redisClient, cfg.Jobs)
+		jobController = controller.NewJobController(service.NewJobService(queue, resumeService, db))
+		queue.Start()
+		log.Printf("✅ 异步任务队列已启动（worker 数量: %d）\n", cfg.Jobs.Workers)
+	} else {
+		log.Println("✅ 异步任务队列未启用")
+	}
+
+	// 定期永久删除回收站中超过保留期限的简历
+	var purger *service.TrashPurger
+	if cfg.Trash.Retention > 0 {
+		purger = service.NewTrashPurger(db, cfg.Trash.Retention, cfg.Trash.PurgeInterval)
+		purger.Start()
+	}
+
+	r := route.Run(cfg.HTTP, resumeController, jobController)
+	srv := &http.Server{
+		Addr:              cfg.HTTP.Addr,
+		Handler:           r,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
 
 	// 启动服务器
 	log.Println("🚀 服务器启动中...")
-	log.Println("📡 监听地址: http://localhost:8080")
-	if err := r.Run(":8080"); err != nil {
-		log.Fatal("❌ 服务启动失败： ", err)
+	log.Printf("📡 监听地址: %s\n", cfg.HTTP.Addr)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	// 等待退出信号（Ctrl+C 或部署时的 SIGTERM）
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	exitCode := 0
+	select {
+	case err := <-serveErr:
+		log.Println("❌ 服务启动失败： ", err)
+		exitCode = 1
+	case <-ctx.Done():
+		// 恢复默认信号处理，再次收到信号时立即退出
+		stop()
+		log.Printf("🛑 收到退出信号，最长等待 %s 完成进行中的请求和任务...\n", cfg.HTTP.ShutdownTimeout)
+	}
+
+	shutdown(cfg.HTTP.ShutdownTimeout, srv, queue, purger, db, redisClient, provider)
+	os.Exit(exitCode)
+}
+
+// shutdown 按依赖顺序释放资源：先停止接收请求和任务并等待其完成，再关闭数据库、Redis和AI提供方。
+// 未启用任务队列时 queue 为 nil，回收站永久保留时 purger 为 nil，不需要 Redis 时 redisClient 为 nil
+func shutdown(timeout time.Duration, srv *http.Server, queue *job.Queue, purger *service.TrashPurger, db dao.ResumeDAO, redisClient *redis.Client, provider agent.ChatProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// HTTP请求与异步任务共用同一个等待期限，并行排空
+	var wg sync.WaitGroup
+	if queue != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := queue.Shutdown(ctx); err != nil {
+				log.Printf("⚠️ 部分异步任务未能在期限内完成，已放回队列: %v\n", err)
+			}
+		}()
+	}
+	if err := srv.Shutdown(ctx); err != nil {
+		// 强制关闭连接，进行中请求的 ctx 随之结束，模型调用和数据库操作中止
+		log.Printf("⚠️ 部分请求未能在期限内完成，强制关闭连接: %v\n", err)
+		srv.Close()
+	}
+	wg.Wait()
+	if purger != nil {
+		purger.Stop()
+	}
+
+	if err := db.Close(); err != nil {
+		log.Printf("⚠️ 关闭数据库连接失败: %v\n", err)
+	}
+	if redisClient != nil {
+		if err := redisClient.Close(); err != nil {
+			log.Printf("⚠️ 关闭Redis连接失败: %v\n", err)
+		}
+	}
+	if c, ok := provider.(io.Closer); ok {
+		if err := c.Close(); err != nil {
+			log.Printf("⚠️ 关闭AI提供方失败: %v\n", err)
+		}
 	}
+	log.Println("👋 服务已退出")
 }
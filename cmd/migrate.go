@@ -0,0 +1,75 @@
+package main
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/migration"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+)
+
+// migrateUsage migrate 子命令的用法
+const migrateUsage = `用法: migrate <up|down [步数]|status>
+  up      执行全部未执行的迁移
+  down    回滚最近执行的迁移，默认 1 步
+  status  列出全部迁移及其执行状态`
+
+// runMigrate 执行 migrate 子命令，不启动服务
+func runMigrate(cfg config.DBConfig, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	if cfg.Driver == "memory" {
+		return errors.New("进程内存储（DB_DRIVER=memory）不需要迁移")
+	}
+
+	db, err := dao.OpenDB(cfg)
+	if err != nil {
+		return err
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		done, err := migration.Up(ctx, db)
+		for _, m := range done {
+			log.Printf("✅ 已执行迁移 %d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			log.Println("✅ 数据库结构已是最新")
+		}
+		return err
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("步数必须是正整数: %s", args[1])
+			}
+		}
+		done, err := migration.Down(ctx, db, steps)
+		for _, m := range done {
+			log.Printf("✅ 已回滚迁移 %d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migration.List(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, s := range statuses {
+			if s.Applied {
+				fmt.Printf("%4d  %-32s  已执行 %s\n", s.Version, s.Name, s.AppliedAt.Format("2006-01-02 15:04:05"))
+			} else {
+				fmt.Printf("%4d  %-32s  未执行\n", s.Version, s.Name)
+			}
+		}
+		return nil
+	}
+	return errors.New(migrateUsage)
+}
@@ -1,58 +1,101 @@
 package agent
 
 import (
+	"ResumeBuilder/internal/config"
 	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/resilience"
 	"ResumeBuilder/internal/utils"
+	"ResumeBuilder/internal/validation"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
 	"strings"
-
-	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
-	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
-	"os"
 )
 
-// AIAgent 是我们自己定义的接口，包含初始化客户端和解析简历的方法
+// AIAgent 是我们自己定义的接口，包含解析简历和分析GitHub项目的方法
+// 具体使用哪个大模型厂商由注入的 ChatProvider 决定
 type AIAgent interface {
-	InitializeClient() (*arkruntime.Client, error)
-	ParseResume(ctx context.Context, client *arkruntime.Client, raw string) (*domain.Resume, error)
-	AnalyzeGitHubRepo(ctx context.Context, client *arkruntime.Client, repoURL string) (*domain.Project, error)
+	ParseResume(ctx context.Context, raw string) (*domain.Resume, error)
+	AnalyzeGitHubRepo(ctx context.Context, repoURL string) (*domain.Project, error)
+
+	// 以下流式版本通过 progress 报告阶段变化，并在设置了 OnDelta 时流式返回模型输出
+	ParseResumeStream(ctx context.Context, raw string, progress Progress) (*domain.Resume, error)
+	AnalyzeGitHubRepoStream(ctx context.Context, repoURL string, progress Progress) (*domain.Project, error)
 }
 
+var (
+	// ErrModel 调用大模型失败，或模型输出经修正后仍无法使用
+	ErrModel = errors.New("AI服务调用失败")
+	// ErrGitHub GitHub限流或不可用，无法获取项目信息
+	ErrGitHub = errors.New("GitHub请求失败")
+)
+
 // 实现 AIAgent 接口的结构体
 type agent struct {
-	client *arkruntime.Client
+	provider    ChatProvider
+	cfg         config.AIConfig
+	githubToken string
+	validator   *validation.Validator
+	// GitHub API 与 raw.githubusercontent.com 分别熔断，API 不可用时仍可降级到 raw 地址
+	githubAPI *resilience.Client
+	githubRaw *resilience.Client
 }
 
 // NewAIAgent 返回一个实现 AIAgent 接口的 agent 对象
-func NewAIAgent() AIAgent {
-	return &agent{}
+// 模型输出未通过 validator 的校验时，会把问题反馈给模型修正
+func NewAIAgent(provider ChatProvider, cfg config.AIConfig, github config.GitHubConfig, validator *validation.Validator) AIAgent {
+	return &agent{
+		provider:    provider,
+		cfg:         cfg,
+		githubToken: github.Token,
+		validator:   validator,
+		githubAPI:   resilience.New("github-api", github.Retry),
+		githubRaw:   resilience.New("github-raw", github.Retry),
+	}
 }
 
-// InitializeClient 实现 AIAgent 接口的 InitializeClient 方法
-func (a *agent) InitializeClient() (*arkruntime.Client, error) {
-	// 从环境变量获取 API Key
-	apiKey := os.Getenv("apiKey")
-	if apiKey == "" {
-		return nil, fmt.Errorf("API Key is missing")
-	}
+// complete 按任务配置调用模型并返回文本结果
+func (a *agent) complete(ctx context.Context, task config.TaskConfig, messages []ChatMessage, progress Progress) (string, error) {
+	progress.Enter(StageCallingModel)
 
-	// 初始化 Ark 客户端
-	client := arkruntime.NewClientWithApiKey(
-		apiKey,
-		arkruntime.WithBaseUrl("https://ark.cn-beijing.volces.com/api/v3"),
-	)
+	timeout := task.Timeout
+	if timeout == 0 {
+		timeout = a.cfg.Timeout
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
-	// 将客户端保存到结构体中
-	a.client = client
+	req := ChatRequest{
+		Model:       task.Model,
+		Messages:    messages,
+		Temperature: task.Temperature,
+		MaxTokens:   task.MaxTokens,
+	}
 
-	return a.client, nil
+	var resp *ChatResponse
+	var err error
+	if progress.streaming() {
+		resp, err = a.provider.ChatStream(ctx, req, progress.OnDelta)
+	} else {
+		resp, err = a.provider.Chat(ctx, req)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrModel, err)
+	}
+	return resp.Content, nil
 }
 
 // ParseResume 实现 AIAgent 接口的 ParseResume 方法
-func (a *agent) ParseResume(ctx context.Context, client *arkruntime.Client, raw string) (*domain.Resume, error) {
+func (a *agent) ParseResume(ctx context.Context, raw string) (*domain.Resume, error) {
+	return a.ParseResumeStream(ctx, raw, Progress{})
+}
+
+// ParseResumeStream 实现 AIAgent 接口的 ParseResumeStream 方法
+func (a *agent) ParseResumeStream(ctx context.Context, raw string, progress Progress) (*domain.Resume, error) {
 
 	// 构建简历生成的提示文本，要求生成结构化 JSON 简历
 	prompt := fmt.Sprintf(`
@@ -65,15 +108,25 @@ func (a *agent) ParseResume(ctx context.Context, client *arkruntime.Client, raw
 	4. 没有信息的字段保持为空值，不要编造或填充任何内容
 	5. skills 字段必须是完整的描述性语句，每条技能都要用"熟悉"、"掌握"、"了解"、"精通"等程度词开头
 	6. 只返回纯 JSON 格式，不要添加任何 markdown 代码块标记（不要使用三个反引号包裹）
+	7. summary 只填写文本中的个人简介、自我评价或求职意向原文，不要自行概括生成
+	8. 证书、获奖、语言能力、个人链接（GitHub、博客、作品集等）和论文著作分别放入 certifications、awards、languages、links、publications，不要混入 skills 或其他章节
+	9. 文本中无法归入以上任何章节的标题（如"志愿经历"、"开源贡献"、"专利"）放入 custom_sections，title 使用文本中的原标题，章节和条目保持在文本中出现的顺序；没有这类标题时 custom_sections 为空数组
 
 	请按照以下结构输出纯 JSON 格式：
 	{
 		"user_id": "用户ID",
 		"basic_info": [{"name": "姓名", "email": "邮箱", "phone": "电话", "location": "位置", "title": "职位"}],
-		"education": [{"school": "学校", "major": "专业", "start_date": "开始日期", "end_date": "结束日期", "degree": "学位"}],
-		"experience": [{"company": "公司", "position": "职位", "start_date": "开始日期", "end_date": "结束日期", "description": "描述", "achievements": ["成就1", "成就2"]}],
+		"summary": "个人简介",
+		"education": [{"school": "学校", "major": "专业", "start_date": "开始日期，如 2019-09", "end_date": "结束日期，如 2023-06，在读写 至今", "degree": "学位"}],
+		"experience": [{"company": "公司", "position": "职位", "start_date": "开始日期，如 2019-09", "end_date": "结束日期，在职写 至今", "description": "描述", "achievements": ["成就1", "成就2"]}],
 		"projects": [{"name": "项目名称", "role": "角色", "description": "项目描述", "tech_stack": ["技术栈1", "技术栈2"], "highlights": ["亮点1", "亮点2"]}],
-		"skills": ["熟悉使用 Go 语言进行后端开发", "掌握 TCP/IP 网络协议模型", "了解分布式系统设计"]
+		"skills": ["熟悉使用 Go 语言进行后端开发", "掌握 TCP/IP 网络协议模型", "了解分布式系统设计"],
+		"certifications": [{"name": "证书名称", "issuer": "颁发机构", "date": "获得日期，如 2022-05", "url": "证书链接"}],
+		"awards": [{"title": "奖项名称", "issuer": "颁发机构", "date": "获奖日期", "description": "说明"}],
+		"languages": [{"language": "语种", "proficiency": "熟练程度，如 母语、CET-6、IELTS 7.0"}],
+		"links": [{"label": "链接名称，如 GitHub", "url": "完整URL，以 http:// 或 https:// 开头"}],
+		"publications": [{"title": "标题", "publisher": "期刊、会议或出版社", "date": "发表日期", "url": "链接", "description": "说明"}],
+		"custom_sections": [{"title": "章节原标题，如 志愿经历", "entries": [{"title": "条目名称", "subtitle": "组织、角色或编号", "start_date": "开始日期", "end_date": "结束日期", "bullets": ["要点1", "要点2"], "url": "链接"}]}]
 	}
 
 	技能格式说明：
@@ -85,74 +138,65 @@ func (a *agent) ParseResume(ctx context.Context, client *arkruntime.Client, raw
 	%s
 	`, raw)
 
-	// 构建请求
-	req := model.CreateChatCompletionRequest{
-		Model: "deepseek-r1-250528",
-		Messages: []*model.ChatCompletionMessage{
-			{
-				Role: model.ChatMessageRoleUser,
-				Content: &model.ChatCompletionMessageContent{
-					ListValue: []*model.ChatCompletionMessageContentPart{
-						{
-							Type: model.ChatCompletionMessageContentPartTypeText,
-							Text: prompt,
-						},
-					},
-				},
-			},
-		},
-	}
-
-	// 发起 API 请求生成简历
-	resp, err := client.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("Error occurred while generating resume: %v", err)
+	// 发起 API 请求生成简历，输出不合法时要求模型修正一次
+	var resume domain.Resume
+	check := func() error { return a.validator.Resume(&resume) }
+	if err := a.generate(ctx, a.cfg.Resume, prompt, progress, &resume, check); err != nil {
+		return nil, fmt.Errorf("Error occurred while generating resume: %w", err)
 	}
 
-	// 输出返回的 JSON 格式简历
-	if len(resp.Choices) > 0 && resp.Choices[0].Message.Content.StringValue != nil {
-		// 清理AI返回的JSON（移除markdown代码块标记）
-		cleanedJSON := cleanAIResponse(*resp.Choices[0].Message.Content.StringValue)
-
-		// 将生成的文本转为 JSON 格式
-		var resume domain.Resume
-		err := json.Unmarshal([]byte(cleanedJSON), &resume)
-		if err != nil {
-			return nil, fmt.Errorf("Error unmarshalling JSON: %v", err)
-		}
-
-		// 返回生成的结构化简历
-		return &resume, nil
-	} else {
-		return nil, fmt.Errorf("No resume generated")
-	}
+	// 返回生成的结构化简历
+	return &resume, nil
 }
 
 // AnalyzeGitHubRepo 分析GitHub项目并返回Project结构体
-func (a *agent) AnalyzeGitHubRepo(ctx context.Context, client *arkruntime.Client, repoURL string) (*domain.Project, error) {
+func (a *agent) AnalyzeGitHubRepo(ctx context.Context, repoURL string) (*domain.Project, error) {
+	return a.AnalyzeGitHubRepoStream(ctx, repoURL, Progress{})
+}
 
-	token := os.Getenv("GITHUB_TOKEN") // 从环境变量获取认证token（公开文件可留空）
+// AnalyzeGitHubRepoStream 实现 AIAgent 接口的 AnalyzeGitHubRepoStream 方法
+func (a *agent) AnalyzeGitHubRepoStream(ctx context.Context, repoURL string, progress Progress) (*domain.Project, error) {
+
+	token := a.githubToken // 认证token（公开文件可留空）
 
 	var fileContent string
 	var err error
 	var repoMetadata *utils.GitHubRepoMetadata
 
+	progress.Enter(StageFetchingReadme)
+
 	// 策略1: 优先使用GitHub API获取README（更稳定，适合国内网络）
 	fmt.Printf("\n📥 正在通过GitHub API获取README...\n")
-	fileContent, err = utils.FetchREADMEViaAPI(ctx, repoURL, token)
+	err = a.githubAPI.Do(ctx, func(ctx context.Context) error {
+		var err error
+		fileContent, err = utils.FetchREADMEViaAPI(ctx, repoURL, token)
+		return err
+	})
 
 	// 策略2: 如果API失败，降级使用raw.githubusercontent.com
 	if err != nil {
 		fmt.Printf("\n⚠️  GitHub API获取失败: %v\n", err)
 		fmt.Printf("📥 尝试使用raw.githubusercontent.com...\n")
-		fileContent, err = utils.FetchREADME(ctx, repoURL, token)
+		err = a.githubRaw.Do(ctx, func(ctx context.Context) error {
+			var err error
+			fileContent, err = utils.FetchREADME(ctx, repoURL, token)
+			return err
+		})
 		if err != nil {
 			fmt.Printf("\n⚠️  README获取失败: %v\n", err)
 			// 策略3: 尝试获取仓库元数据作为备选
 			fmt.Printf("📥 尝试获取仓库元数据作为备选...\n")
-			repoMetadata, err = utils.FetchRepoMetadata(ctx, repoURL, token)
+			err = a.githubAPI.Do(ctx, func(ctx context.Context) error {
+				var err error
+				repoMetadata, err = utils.FetchRepoMetadata(ctx, repoURL, token)
+				return err
+			})
 			if err != nil {
 				fmt.Printf("⚠️  元数据获取也失败: %v\n", err)
+				// GitHub 限流或不可用时没有可分析的内容，直接返回以便提示用户稍后重试
+				if resilience.IsRateLimited(err) || resilience.IsUnavailable(err) {
+					return nil, fmt.Errorf("%w: %w", ErrGitHub, err)
+				}
 				fileContent = ""
 			} else {
 				// 使用元数据构建简单的描述
@@ -177,6 +221,11 @@ func (a *agent) AnalyzeGitHubRepo(ctx context.Context, client *arkruntime.Client
 		fmt.Printf("✓ GitHub API获取README成功\n")
 	}
 
+	// 请求已取消或超时：不再用不完整的内容调用模型
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	prompt := fmt.Sprintf(`
 请深度分析以下GitHub项目的README.md，提取技术信息用于简历展示。
 
@@ -219,52 +268,81 @@ func (a *agent) AnalyzeGitHubRepo(ctx context.Context, client *arkruntime.Client
 - 如果README内容为空，请从URL推断项目基本信息
 `, repoURL, fileContent, repoURL)
 
-	req := model.CreateChatCompletionRequest{
-		Model: "deepseek-r1-250528",
-		Messages: []*model.ChatCompletionMessage{
-			{
-				Role: model.ChatMessageRoleUser,
-				Content: &model.ChatCompletionMessageContent{
-					ListValue: []*model.ChatCompletionMessageContentPart{
-						{Type: model.ChatCompletionMessageContentPartTypeText, Text: prompt},
-					},
-				},
-			},
-		},
+	var project domain.Project
+	check := func() error { return a.validator.Project("", &project) }
+	if err := a.generate(ctx, a.cfg.GitHub, prompt, progress, &project, check, "name"); err != nil {
+		return nil, fmt.Errorf("分析项目失败: %w", err)
+	}
+
+	// 清理所有数字和量化数据
+	project.Description = removeNumbers(project.Description)
+	for i := range project.Highlights {
+		project.Highlights[i] = removeNumbers(project.Highlights[i])
 	}
 
-	resp, err := client.CreateChatCompletion(ctx, req)
+	return &project, nil
+}
+
+// generate 调用模型并将输出解码到 target（见 decodeOutput），再用 check 校验内容。
+// 输出未通过校验时把问题反馈给模型重新生成一次，仍不合法则返回包装了 ErrModel 和 *OutputError 的错误
+func (a *agent) generate(ctx context.Context, task config.TaskConfig, prompt string, progress Progress, target any, check func() error, required ...string) error {
+	messages := []ChatMessage{{Role: RoleUser, Content: prompt}}
+	content, err := a.complete(ctx, task, messages, progress)
 	if err != nil {
-		return nil, fmt.Errorf("分析项目失败: %v", err)
+		return err
 	}
 
-	if len(resp.Choices) > 0 && resp.Choices[0].Message.Content.StringValue != nil {
-		var project domain.Project
-		// 清理AI返回的JSON（移除markdown代码块标记）
-		cleanedJSON := cleanAIResponse(*resp.Choices[0].Message.Content.StringValue)
-		if err := json.Unmarshal([]byte(cleanedJSON), &project); err != nil {
-			return nil, fmt.Errorf("解析结果失败: %v", err)
-		}
+	progress.Enter(StageValidating)
+	err = checkOutput(content, target, check, required...)
+	var outErr *OutputError
+	if !errors.As(err, &outErr) {
+		return err
+	}
 
-		// 清理所有数字和量化数据
-		project.Description = removeNumbers(project.Description)
-		for i := range project.Highlights {
-			project.Highlights[i] = removeNumbers(project.Highlights[i])
-		}
+	progress.Enter(StageRepairing)
+	messages = append(messages,
+		ChatMessage{Role: RoleAssistant, Content: content},
+		ChatMessage{Role: RoleUser, Content: repairPrompt(outErr.Problems)},
+	)
+	content, err = a.complete(ctx, task, messages, progress)
+	if err != nil {
+		return err
+	}
+
+	progress.Enter(StageValidating)
+	if err := checkOutput(content, target, check, required...); err != nil {
+		return fmt.Errorf("%w: %w", ErrModel, err)
+	}
+	return nil
+}
+
+// checkOutput 解码模型输出并校验内容，内容校验的问题同样以 *OutputError 返回
+func checkOutput(content string, target any, check func() error, required ...string) error {
+	if err := decodeOutput(content, target, required...); err != nil {
+		return err
+	}
 
-		return &project, nil
+	err := check()
+	var invalid *domain.ValidationError
+	if !errors.As(err, &invalid) {
+		return err
+	}
+	problems := make([]string, 0, len(invalid.Fields))
+	for _, f := range invalid.Fields {
+		problems = append(problems, f.String())
 	}
-	return nil, fmt.Errorf("未生成分析结果")
+	return &OutputError{Problems: problems}
 }
 
-// cleanAIResponse 清理AI返回的JSON字符串，移除markdown代码块标记
-func cleanAIResponse(raw string) string {
-	// 移除markdown代码块标记
-	raw = strings.TrimSpace(raw)
-	raw = strings.TrimPrefix(raw, "```json")
-	raw = strings.TrimPrefix(raw, "```")
-	raw = strings.TrimSuffix(raw, "```")
-	return strings.TrimSpace(raw)
+// repairPrompt 要求模型根据校验问题修正输出
+func repairPrompt(problems []string) string {
+	var b strings.Builder
+	b.WriteString("你上一次的输出无法通过校验，存在以下问题：\n")
+	for _, p := range problems {
+		b.WriteString("- " + p + "\n")
+	}
+	b.WriteString("请按原要求的结构修正，只返回一个完整的纯 JSON 对象，不要添加任何说明文字或 markdown 代码块标记。")
+	return b.String()
 }
 
 // removeNumbers 清理文本中的所有数字、百分比和量化数据
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var (
+	// errNoJSON 模型输出中找不到 JSON 对象
+	errNoJSON = errors.New("输出中未找到 JSON 对象")
+	// errTruncated 模型输出中的 JSON 对象未闭合，通常是输出超出了长度限制
+	errTruncated = errors.New("输出不完整：JSON 对象未闭合，可能超出了长度限制，请输出完整且更精简的 JSON")
+)
+
+// extractJSON 从模型输出中提取 JSON 对象：去掉 <think> 推理内容、代码块标记和前后的说明文字。
+// 输出被截断（对象未闭合）时返回 errTruncated，缺少末尾内容的结果不可信，需要模型重新生成
+func extractJSON(content string) (string, error) {
+	content = stripThink(content)
+
+	// 说明文字中也可能出现 {，依次尝试每个候选起点
+	for start := strings.IndexByte(content, '{'); start >= 0; {
+		end, closed := matchObject(content, start)
+		if !closed {
+			// 之后的候选都在这个未闭合的对象之内，其中完整的子对象不是结果
+			return "", errTruncated
+		}
+		if candidate := content[start : end+1]; json.Valid([]byte(candidate)) {
+			return candidate, nil
+		}
+
+		next := strings.IndexByte(content[start+1:], '{')
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	return "", errNoJSON
+}
+
+// stripThink 去掉推理模型（如 DeepSeek-R1）输出中的 <think>...</think> 推理内容
+func stripThink(content string) string {
+	if i := strings.LastIndex(content, "</think>"); i >= 0 {
+		// 部分服务不返回开始标签，以最后一个结束标签为准
+		return content[i+len("</think>"):]
+	}
+	if i := strings.Index(content, "<think>"); i >= 0 {
+		// 推理尚未结束输出就被截断，推理内容之后没有正式结果
+		return content[:i]
+	}
+	return content
+}
+
+// matchObject 从 start 处的 { 开始查找与之匹配的 }，忽略字符串中的括号；
+// closed 为 false 表示到结尾仍未闭合
+func matchObject(s string, start int) (end int, closed bool) {
+	depth := 0
+	inString, escaped := false, false
+	for i := start; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			depth++
+		case '}', ']':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	return len(s), false
+}
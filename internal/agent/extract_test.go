@@ -0,0 +1,52 @@
+package agent
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExtractJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"纯 JSON", `{"name":"张三"}`, `{"name":"张三"}`},
+		{"代码块", "```json\n{\"name\":\"张三\"}\n```", `{"name":"张三"}`},
+		{"前后有说明文字", `以下是结果：{"skills":["Go"]} 希望对你有帮助`, `{"skills":["Go"]}`},
+		{"说明文字中的括号", `格式为 {字段: 值}，结果：{"a":1}`, `{"a":1}`},
+		{"字符串中的括号", `{"desc":"使用 {} 和 [] 以及 \"引号\""}`, `{"desc":"使用 {} 和 [] 以及 \"引号\""}`},
+		{"推理内容", `<think>先输出 {"a":0}</think>{"a":1}`, `{"a":1}`},
+		{"缺少推理开始标签", `分析……</think>{"a":1}`, `{"a":1}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractJSON(tt.content)
+			if err != nil || got != tt.want {
+				t.Errorf("extractJSON(%q) = %q, %v，期望 %q", tt.content, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractJSONInvalid(t *testing.T) {
+	tests := []struct {
+		content string
+		want    error
+	}{
+		{"", errNoJSON},
+		{"抱歉，我无法处理这份简历", errNoJSON},
+		{`<think>推理到一半被截断 {"a":1}`, errNoJSON},
+		{"[1, 2, 3]", errNoJSON},
+		{`格式为 {字段: 值}`, errNoJSON},
+		{`{"name":"张三","summary":"五年后端`, errTruncated},
+		{`{"skills":["Go","My`, errTruncated},
+		{`{"name":"张三\`, errTruncated},
+		{"```json\n{\"a\":[{\"b\":1},", errTruncated},
+	}
+	for _, tt := range tests {
+		if got, err := extractJSON(tt.content); !errors.Is(err, tt.want) {
+			t.Errorf("extractJSON(%q) = %q, %v，期望 %v", tt.content, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package agent
+
+// Stage 生成过程所处的阶段
+type Stage string
+
+const (
+	StageFetchingReadme Stage = "fetching_readme" // 获取GitHub仓库README
+	StageCallingModel   Stage = "calling_model"   // 调用大模型
+	StageValidating     Stage = "validating"      // 解析并校验模型输出
+	StageRepairing      Stage = "repairing"       // 模型输出未通过校验，要求模型修正
+	StageSaving         Stage = "saving"          // 写入简历
+)
+
+// Progress 接收生成过程中的阶段变化与模型输出片段，两个回调均可为空
+type Progress struct {
+	OnStage func(stage Stage)
+	OnDelta func(delta StreamDelta)
+}
+
+// Enter 通知进入新阶段
+func (p Progress) Enter(s Stage) {
+	if p.OnStage != nil {
+		p.OnStage(s)
+	}
+}
+
+// streaming 是否需要以流式方式调用模型
+func (p Progress) streaming() bool {
+	return p.OnDelta != nil
+}
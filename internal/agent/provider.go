@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/resilience"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// 对话消息角色
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// ChatMessage 与厂商无关的对话消息
+type ChatMessage struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest 与厂商无关的对话补全请求
+type ChatRequest struct {
+	Model       string
+	Messages    []ChatMessage
+	Temperature *float32 // 为空时使用模型默认值
+	MaxTokens   int      // 0 表示不限制
+}
+
+// ChatResponse 与厂商无关的对话补全结果
+type ChatResponse struct {
+	Content string
+}
+
+// StreamDelta 流式输出中的一个片段
+type StreamDelta struct {
+	Content   string `json:"content,omitempty"`   // 正式输出
+	Reasoning string `json:"reasoning,omitempty"` // 推理模型（如 DeepSeek-R1）的思考过程
+}
+
+// ChatProvider 大模型对话补全能力的抽象，service 层和 agent 不再直接依赖任何厂商SDK类型
+type ChatProvider interface {
+	// Name 返回提供方名称，用于日志
+	Name() string
+	// Chat 发起一次非流式对话补全
+	Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error)
+	// ChatStream 发起流式对话补全，每收到一个片段调用一次 onDelta，结束后返回完整的正式输出
+	ChatStream(ctx context.Context, req ChatRequest, onDelta func(StreamDelta)) (*ChatResponse, error)
+}
+
+// 支持的提供方类型
+const (
+	ProviderArk    = "ark"
+	ProviderOpenAI = "openai"
+	ProviderFake   = "fake"
+)
+
+// NewProvider 根据配置的提供方类型创建 ChatProvider，并按 cfg.Retry 加上重试与熔断
+func NewProvider(cfg config.AIConfig) (ChatProvider, error) {
+	var p ChatProvider
+	switch strings.ToLower(cfg.Provider) {
+	case "", ProviderArk:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("API Key is missing")
+		}
+		p = NewArkProvider(cfg.APIKey, cfg.BaseURL)
+	case ProviderOpenAI:
+		if cfg.APIKey == "" {
+			return nil, fmt.Errorf("API Key is missing")
+		}
+		p = NewOpenAIProvider(cfg.APIKey, cfg.BaseURL)
+	case ProviderFake:
+		p = NewFakeProvider()
+	default:
+		return nil, fmt.Errorf("不支持的AI提供方: %s", cfg.Provider)
+	}
+	return WithResilience(p, resilience.New(p.Name(), cfg.Retry)), nil
+}
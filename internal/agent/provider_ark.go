@@ -0,0 +1,169 @@
+package agent
+
+import (
+	"ResumeBuilder/internal/resilience"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime"
+	"github.com/volcengine/volcengine-go-sdk/service/arkruntime/model"
+)
+
+// defaultArkBaseURL 火山方舟默认接入地址
+const defaultArkBaseURL = "https://ark.cn-beijing.volces.com/api/v3"
+
+// arkProvider 基于火山方舟 SDK 的 ChatProvider 实现
+type arkProvider struct {
+	client *arkruntime.Client
+}
+
+// NewArkProvider 创建火山方舟提供方，baseURL 为空时使用默认地址
+func NewArkProvider(apiKey, baseURL string) ChatProvider {
+	if baseURL == "" {
+		baseURL = defaultArkBaseURL
+	}
+	return &arkProvider{
+		client: arkruntime.NewClientWithApiKey(
+			apiKey,
+			arkruntime.WithBaseUrl(baseURL),
+			// 重试由 resilience 统一处理，关闭SDK自带的重试避免次数叠加
+			arkruntime.WithRetryTimes(0),
+			arkruntime.WithHTTPClient(&http.Client{
+				Timeout:   10 * time.Minute,
+				Transport: headerTransport{base: http.DefaultTransport},
+			}),
+		),
+	}
+}
+
+// headerKey 上下文中保存失败响应头的位置。SDK 返回的错误不包含响应头，
+// 由 headerTransport 记录，arkError 从中读取 Retry-After
+type headerKey struct{}
+
+// withHeader 返回记录失败响应头的上下文
+func withHeader(ctx context.Context) (context.Context, *http.Header) {
+	header := &http.Header{}
+	return context.WithValue(ctx, headerKey{}, header), header
+}
+
+// headerTransport 将失败响应的响应头写入请求上下文中的 headerKey
+type headerTransport struct {
+	base http.RoundTripper
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err == nil && resp.StatusCode >= http.StatusBadRequest {
+		if header, ok := req.Context().Value(headerKey{}).(*http.Header); ok {
+			*header = resp.Header
+		}
+	}
+	return resp, err
+}
+
+func (p *arkProvider) Name() string {
+	return ProviderArk
+}
+
+func (p *arkProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	ctx, header := withHeader(ctx)
+	resp, err := p.client.CreateChatCompletion(ctx, toArkRequest(req))
+	if err != nil {
+		return nil, arkError(err, *header)
+	}
+
+	if len(resp.Choices) == 0 || resp.Choices[0].Message.Content == nil ||
+		resp.Choices[0].Message.Content.StringValue == nil {
+		return nil, fmt.Errorf("模型未返回内容")
+	}
+	return &ChatResponse{Content: *resp.Choices[0].Message.Content.StringValue}, nil
+}
+
+func (p *arkProvider) ChatStream(ctx context.Context, req ChatRequest, onDelta func(StreamDelta)) (*ChatResponse, error) {
+	ctx, header := withHeader(ctx)
+	stream, err := p.client.CreateChatCompletionStream(ctx, toArkRequest(req))
+	if err != nil {
+		return nil, arkError(err, *header)
+	}
+	defer stream.Close()
+
+	var content strings.Builder
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, arkError(err, nil)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := StreamDelta{Content: chunk.Choices[0].Delta.Content}
+		if chunk.Choices[0].Delta.ReasoningContent != nil {
+			delta.Reasoning = *chunk.Choices[0].Delta.ReasoningContent
+		}
+		if delta.Content == "" && delta.Reasoning == "" {
+			continue
+		}
+		content.WriteString(delta.Content)
+		onDelta(delta)
+	}
+
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("模型未返回内容")
+	}
+	return &ChatResponse{Content: content.String()}, nil
+}
+
+// arkError 将SDK错误转换为 *resilience.Error，以便按状态码重试和映射HTTP状态码；
+// header 为失败响应的响应头（未知时为 nil），用于解析 Retry-After
+func arkError(err error, header http.Header) error {
+	var apiErr *model.APIError
+	var reqErr *model.RequestError
+	var urlErr *url.Error
+	switch {
+	case errors.As(err, &apiErr):
+		return resilience.NewHTTPError(ProviderArk, &http.Response{StatusCode: apiErr.HTTPStatusCode, Header: header}, err)
+	case errors.As(err, &reqErr):
+		return resilience.NewHTTPError(ProviderArk, &http.Response{StatusCode: reqErr.HTTPStatusCode, Header: header}, err)
+	case errors.As(err, &urlErr), errors.Is(err, io.ErrUnexpectedEOF):
+		return resilience.NewNetworkError(ProviderArk, err)
+	}
+	return err
+}
+
+// toArkRequest 将通用请求转换为方舟SDK请求
+func toArkRequest(req ChatRequest) model.CreateChatCompletionRequest {
+	messages := make([]*model.ChatCompletionMessage, 0, len(req.Messages))
+	for _, m := range req.Messages {
+		messages = append(messages, &model.ChatCompletionMessage{
+			Role: m.Role,
+			Content: &model.ChatCompletionMessageContent{
+				ListValue: []*model.ChatCompletionMessageContentPart{
+					{
+						Type: model.ChatCompletionMessageContentPartTypeText,
+						Text: m.Content,
+					},
+				},
+			},
+		})
+	}
+	arkReq := model.CreateChatCompletionRequest{
+		Model:       req.Model,
+		Messages:    messages,
+		Temperature: req.Temperature,
+	}
+	if req.MaxTokens > 0 {
+		maxTokens := req.MaxTokens
+		arkReq.MaxTokens = &maxTokens
+	}
+	return arkReq
+}
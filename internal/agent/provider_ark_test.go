@@ -0,0 +1,87 @@
+package agent
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/resilience"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// rateLimitedArk 模拟方舟接口：前 limited 次请求返回 429 和 Retry-After，之后正常返回
+func rateLimitedArk(t *testing.T, limited int, retryAfter string) (*httptest.Server, func() []time.Time) {
+	var mu sync.Mutex
+	var requests []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, time.Now())
+		n := len(requests)
+		mu.Unlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		if n <= limited {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			w.Write([]byte(`{"error":{"code":"RateLimitExceeded","message":"请求过于频繁","type":"TooManyRequests"}}`))
+			return
+		}
+		w.Write([]byte(`{"choices":[{"index":0,"message":{"role":"assistant","content":"{}"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]time.Time(nil), requests...)
+	}
+}
+
+func TestArkRetryAfter(t *testing.T) {
+	tests := []struct {
+		name string
+		call func(p ChatProvider) error
+	}{
+		{"Chat", func(p ChatProvider) error {
+			_, err := p.Chat(context.Background(), ChatRequest{Model: "m"})
+			return err
+		}},
+		{"ChatStream", func(p ChatProvider) error {
+			_, err := p.ChatStream(context.Background(), ChatRequest{Model: "m"}, func(StreamDelta) {})
+			return err
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, _ := rateLimitedArk(t, 1, "2")
+			err := tt.call(NewArkProvider("key", srv.URL))
+			var upErr *resilience.Error
+			if !errors.As(err, &upErr) || !upErr.RateLimited || upErr.StatusCode != http.StatusTooManyRequests || upErr.RetryAfter != 2*time.Second {
+				t.Fatalf("err = %#v，期望 RetryAfter 为 2s 的限流错误", err)
+			}
+		})
+	}
+}
+
+func TestArkRetryAfterWait(t *testing.T) {
+	srv, requests := rateLimitedArk(t, 1, "2")
+	client := resilience.New(ProviderArk, config.ResilienceConfig{
+		MaxAttempts: 2,
+		BaseDelay:   10 * time.Millisecond,
+		MaxDelay:    5 * time.Second,
+	})
+	p := WithResilience(NewArkProvider("key", srv.URL), client)
+	if _, err := p.Chat(context.Background(), ChatRequest{Model: "m"}); err != nil {
+		t.Fatalf("Chat: %v", err)
+	}
+
+	got := requests()
+	if len(got) != 2 {
+		t.Fatalf("共 %d 次请求，期望 2 次", len(got))
+	}
+	if wait := got[1].Sub(got[0]); wait < 2*time.Second || wait > 3*time.Second {
+		t.Errorf("重试前等待了 %v，期望约 2s", wait)
+	}
+}
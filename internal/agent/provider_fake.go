@@ -0,0 +1,75 @@
+package agent
+
+import (
+	"context"
+	"sync"
+)
+
+// fakeChunkSize 模拟流式输出时每个片段的字符数
+const fakeChunkSize = 16
+
+// FakeProvider 按预设脚本返回结果的 ChatProvider，用于离线演示和测试
+// 每次调用依次返回脚本中的下一条回复，脚本用完后重复最后一条；未设置脚本时返回空 JSON 对象
+type FakeProvider struct {
+	mu        sync.Mutex
+	responses []string
+	calls     int
+	requests  []ChatRequest
+}
+
+// NewFakeProvider 创建按顺序返回 responses 的假提供方
+func NewFakeProvider(responses ...string) *FakeProvider {
+	return &FakeProvider{responses: responses}
+}
+
+func (p *FakeProvider) Name() string {
+	return ProviderFake
+}
+
+func (p *FakeProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests = append(p.requests, req)
+
+	content := "{}"
+	if len(p.responses) > 0 {
+		idx := p.calls
+		if idx >= len(p.responses) {
+			idx = len(p.responses) - 1
+		}
+		content = p.responses[idx]
+	}
+	p.calls++
+
+	return &ChatResponse{Content: content}, nil
+}
+
+// ChatStream 将脚本回复按固定长度切分后逐段回调，模拟流式输出
+func (p *FakeProvider) ChatStream(ctx context.Context, req ChatRequest, onDelta func(StreamDelta)) (*ChatResponse, error) {
+	resp, err := p.Chat(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	runes := []rune(resp.Content)
+	for start := 0; start < len(runes); start += fakeChunkSize {
+		end := min(start+fakeChunkSize, len(runes))
+		onDelta(StreamDelta{Content: string(runes[start:end])})
+	}
+	return resp, nil
+}
+
+// Requests 返回目前收到的全部请求，便于断言提示词内容
+func (p *FakeProvider) Requests() []ChatRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	out := make([]ChatRequest, len(p.requests))
+	copy(out, p.requests)
+	return out
+}
@@ -0,0 +1,201 @@
+package agent
+
+import (
+	"ResumeBuilder/internal/resilience"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// defaultOpenAIBaseURL OpenAI 官方接入地址，自建或第三方兼容服务通过 baseURL 覆盖
+const defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// openAIProvider 基于 OpenAI 兼容 HTTP 接口（/chat/completions）的 ChatProvider 实现
+type openAIProvider struct {
+	apiKey     string
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewOpenAIProvider 创建 OpenAI 兼容提供方，baseURL 为空时使用 OpenAI 官方地址
+func NewOpenAIProvider(apiKey, baseURL string) ChatProvider {
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &openAIProvider{
+		apiKey:  apiKey,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		// 推理模型耗时较长，超时时间需明显大于普通HTTP请求
+		httpClient: &http.Client{Timeout: 10 * time.Minute},
+	}
+}
+
+// openAIMessage OpenAI 接口的消息格式
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatRequest OpenAI 接口的请求体
+type openAIChatRequest struct {
+	Model       string          `json:"model"`
+	Messages    []openAIMessage `json:"messages"`
+	Temperature *float32        `json:"temperature,omitempty"`
+	MaxTokens   int             `json:"max_tokens,omitempty"`
+	Stream      bool            `json:"stream,omitempty"`
+}
+
+// openAIChatResponse OpenAI 接口的响应体（仅保留用到的字段）
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *openAIError `json:"error,omitempty"`
+}
+
+// openAIStreamChunk 流式响应中的一个数据块
+type openAIStreamChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content          string `json:"content"`
+			ReasoningContent string `json:"reasoning_content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Error *openAIError `json:"error,omitempty"`
+}
+
+type openAIError struct {
+	Message string `json:"message"`
+}
+
+func (p *openAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (p *openAIProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	resp, err := p.do(ctx, req, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var result openAIChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("解析响应失败: %w", err)
+	}
+	if len(result.Choices) == 0 {
+		return nil, fmt.Errorf("模型未返回内容")
+	}
+	return &ChatResponse{Content: result.Choices[0].Message.Content}, nil
+}
+
+func (p *openAIProvider) ChatStream(ctx context.Context, req ChatRequest, onDelta func(StreamDelta)) (*ChatResponse, error) {
+	resp, err := p.do(ctx, req, true)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// 响应为 SSE 格式：每个数据块以 "data: " 开头，以 "data: [DONE]" 结束
+	var content strings.Builder
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		data, ok := strings.CutPrefix(line, "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk openAIStreamChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, fmt.Errorf("解析流式响应失败: %w", err)
+		}
+		if chunk.Error != nil {
+			return nil, fmt.Errorf("模型服务返回错误: %s", chunk.Error.Message)
+		}
+		if len(chunk.Choices) == 0 {
+			continue
+		}
+
+		delta := StreamDelta{
+			Content:   chunk.Choices[0].Delta.Content,
+			Reasoning: chunk.Choices[0].Delta.ReasoningContent,
+		}
+		if delta.Content == "" && delta.Reasoning == "" {
+			continue
+		}
+		content.WriteString(delta.Content)
+		onDelta(delta)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, resilience.NewNetworkError(ProviderOpenAI, fmt.Errorf("读取流式响应失败: %w", err))
+	}
+
+	if content.Len() == 0 {
+		return nil, fmt.Errorf("模型未返回内容")
+	}
+	return &ChatResponse{Content: content.String()}, nil
+}
+
+// do 发送请求并检查状态码，调用方负责关闭响应体
+func (p *openAIProvider) do(ctx context.Context, req ChatRequest, stream bool) (*http.Response, error) {
+	body := openAIChatRequest{
+		Model:       req.Model,
+		Temperature: req.Temperature,
+		MaxTokens:   req.MaxTokens,
+		Stream:      stream,
+	}
+	for _, m := range req.Messages {
+		body.Messages = append(body.Messages, openAIMessage{Role: m.Role, Content: m.Content})
+	}
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("序列化请求失败: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/chat/completions", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("创建请求失败: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	if stream {
+		httpReq.Header.Set("Accept", "text/event-stream")
+	}
+
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, resilience.NewNetworkError(ProviderOpenAI, fmt.Errorf("请求模型服务失败: %w", err))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		var result openAIChatResponse
+		if json.Unmarshal(data, &result) == nil && result.Error != nil && result.Error.Message != "" {
+			err = fmt.Errorf("模型服务返回错误，状态码: %d, %s", resp.StatusCode, result.Error.Message)
+		} else {
+			err = fmt.Errorf("模型服务返回错误，状态码: %d", resp.StatusCode)
+		}
+		return nil, resilience.NewHTTPError(ProviderOpenAI, resp, err)
+	}
+	return resp, nil
+}
+
+// Close 关闭空闲连接，在服务退出时调用
+func (p *openAIProvider) Close() error {
+	p.httpClient.CloseIdleConnections()
+	return nil
+}
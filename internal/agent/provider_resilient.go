@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"ResumeBuilder/internal/resilience"
+	"context"
+	"io"
+)
+
+// resilientProvider 为 ChatProvider 增加重试与熔断
+type resilientProvider struct {
+	ChatProvider
+	client *resilience.Client
+}
+
+// WithResilience 用 client 包装 p 的调用：限流、5xx 和网络错误时退避重试，连续失败时熔断
+func WithResilience(p ChatProvider, client *resilience.Client) ChatProvider {
+	return &resilientProvider{ChatProvider: p, client: client}
+}
+
+func (p *resilientProvider) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	var resp *ChatResponse
+	err := p.client.Do(ctx, func(ctx context.Context) error {
+		var err error
+		resp, err = p.ChatProvider.Chat(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// ChatStream 只在尚未输出任何片段时重试，避免调用方收到重复内容
+func (p *resilientProvider) ChatStream(ctx context.Context, req ChatRequest, onDelta func(StreamDelta)) (*ChatResponse, error) {
+	var resp *ChatResponse
+	err := p.client.Do(ctx, func(ctx context.Context) error {
+		emitted := false
+		var err error
+		resp, err = p.ChatProvider.ChatStream(ctx, req, func(d StreamDelta) {
+			emitted = true
+			onDelta(d)
+		})
+		if err != nil && emitted {
+			return resilience.Permanent(err)
+		}
+		return err
+	})
+	return resp, err
+}
+
+// Close 关闭被包装的提供方
+func (p *resilientProvider) Close() error {
+	if c, ok := p.ChatProvider.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
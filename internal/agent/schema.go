@@ -0,0 +1,233 @@
+package agent
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// OutputError 模型输出未通过校验，Problems 为具体问题，会在重新请求时反馈给模型
+type OutputError struct {
+	Problems []string
+}
+
+func (e *OutputError) Error() string {
+	return "模型输出校验失败: " + strings.Join(e.Problems, "; ")
+}
+
+var (
+	timeType        = reflect.TypeOf(time.Time{})
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// decodeOutput 从模型输出中提取 JSON，按 target 的结构（由 Go 类型推导出的 schema）校验并修正常见的类型错误，
+// 再解码到 target。required 为顶层必须非空的字段（JSON 字段名）。无法修正的问题以 *OutputError 返回，
+// 输出被截断时同样返回 *OutputError 让模型重新生成
+func decodeOutput(content string, target any, required ...string) error {
+	object, err := extractJSON(content)
+	if err != nil {
+		return &OutputError{Problems: []string{err.Error()}}
+	}
+
+	dec := json.NewDecoder(strings.NewReader(object))
+	dec.UseNumber()
+	var raw any
+	if err := dec.Decode(&raw); err != nil {
+		return &OutputError{Problems: []string{"JSON 格式错误: " + err.Error()}}
+	}
+
+	var problems []string
+	t := reflect.TypeOf(target).Elem()
+	fixed := coerce(raw, t, "", &problems)
+
+	if obj, ok := fixed.(map[string]any); ok {
+		for _, name := range required {
+			if s, _ := obj[name].(string); strings.TrimSpace(s) == "" {
+				problems = append(problems, name+": 不能为空")
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return &OutputError{Problems: problems}
+	}
+
+	data, err := json.Marshal(fixed)
+	if err != nil {
+		return err
+	}
+	// 修正后重新生成时 target 中可能留有上一次的结果
+	reflect.ValueOf(target).Elem().SetZero()
+	return json.NewDecoder(bytes.NewReader(data)).Decode(target)
+}
+
+// coerce 按类型 t 校验 JSON 值 v 并修正常见的类型错误：
+// 数字/布尔转字符串、字符串数组拼接为字符串、分隔的字符串拆为数组、单个对象包装为数组、null 转为空值。
+// 无法修正的问题追加到 problems，路径形如 experience[2].company
+func coerce(v any, t reflect.Type, path string, problems *[]string) any {
+	if t == timeType {
+		// 时间字段由服务端维护，忽略模型输出
+		return nil
+	}
+	if reflect.PointerTo(t).Implements(unmarshalerType) {
+		// 自定义解码的类型（如 domain.PartialDate）接受字符串和数字，由其自身解析
+		switch v.(type) {
+		case nil, string, json.Number:
+			return v
+		}
+		*problems = append(*problems, fmt.Sprintf("%s: 应为字符串，实际为%s", displayPath(path), valueKind(v)))
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		switch x := v.(type) {
+		case nil:
+			return ""
+		case string:
+			return x
+		case json.Number, bool:
+			return fmt.Sprint(x)
+		case []any:
+			parts := make([]string, 0, len(x))
+			for i, e := range x {
+				parts = append(parts, coerce(e, t, fmt.Sprintf("%s[%d]", path, i), problems).(string))
+			}
+			return strings.Join(parts, "；")
+		}
+
+	case reflect.Bool:
+		switch x := v.(type) {
+		case nil:
+			return false
+		case bool:
+			return x
+		case string:
+			return strings.EqualFold(strings.TrimSpace(x), "true")
+		}
+
+	case reflect.Slice:
+		switch x := v.(type) {
+		case nil:
+			return []any{}
+		case []any:
+			out := make([]any, len(x))
+			for i, e := range x {
+				out[i] = coerce(e, t.Elem(), fmt.Sprintf("%s[%d]", path, i), problems)
+			}
+			return out
+		case string:
+			if t.Elem().Kind() == reflect.String {
+				return splitList(x)
+			}
+		case map[string]any:
+			if t.Elem().Kind() == reflect.Struct {
+				return []any{coerce(x, t.Elem(), path+"[0]", problems)}
+			}
+		}
+
+	case reflect.Struct:
+		obj, ok := v.(map[string]any)
+		if !ok {
+			break
+		}
+		out := make(map[string]any, t.NumField())
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := jsonName(f)
+			if name == "" {
+				continue
+			}
+			fieldPath := name
+			if path != "" {
+				fieldPath = path + "." + name
+			}
+			if fv := coerce(obj[name], f.Type, fieldPath, problems); fv != nil {
+				out[name] = fv
+			}
+		}
+		return out
+
+	default:
+		return v
+	}
+
+	*problems = append(*problems, fmt.Sprintf("%s: 应为%s，实际为%s", displayPath(path), kindName(t), valueKind(v)))
+	return reflect.Zero(t).Interface()
+}
+
+// splitList 将模型误输出为单个字符串的列表拆分为数组，优先按换行和分号拆分
+func splitList(s string) []any {
+	var parts []string
+	switch {
+	case strings.ContainsAny(s, "\n；;"):
+		parts = strings.FieldsFunc(s, func(r rune) bool { return r == '\n' || r == '；' || r == ';' })
+	default:
+		parts = strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '，' || r == '、' })
+	}
+
+	out := make([]any, 0, len(parts))
+	for _, p := range parts {
+		p = strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(p), "-•*·"))
+		if p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// jsonName 字段的 JSON 名称，不参与序列化的字段返回空
+func jsonName(f reflect.StructField) string {
+	if !f.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return f.Name
+	}
+	return name
+}
+
+func displayPath(path string) string {
+	if path == "" {
+		return "根对象"
+	}
+	return path
+}
+
+func kindName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "字符串"
+	case reflect.Bool:
+		return "布尔值"
+	case reflect.Slice:
+		return "数组"
+	case reflect.Struct:
+		return "对象"
+	}
+	return t.Kind().String()
+}
+
+func valueKind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "字符串"
+	case json.Number:
+		return "数字"
+	case bool:
+		return "布尔值"
+	case []any:
+		return "数组"
+	case map[string]any:
+		return "对象"
+	}
+	return fmt.Sprintf("%T", v)
+}
@@ -0,0 +1,158 @@
+package agent
+
+import (
+	"ResumeBuilder/internal/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type sampleItem struct {
+	Title   string   `json:"title"`
+	Bullets []string `json:"bullets"`
+}
+
+type sample struct {
+	Name      string             `json:"name"`
+	Active    bool               `json:"active"`
+	Tags      []string           `json:"tags"`
+	Items     []sampleItem       `json:"items"`
+	Date      domain.PartialDate `json:"date"`
+	CreatedAt time.Time          `json:"created_at"`
+	Internal  string             `json:"-"`
+}
+
+func TestCoerce(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		want     string
+		problems []string
+	}{
+		{
+			"类型正确",
+			`{"name":"张三","active":true,"tags":["Go"],"items":[{"title":"a","bullets":["x"]}],"date":"2019-09"}`,
+			`{"active":true,"date":"2019-09","items":[{"bullets":["x"],"title":"a"}],"name":"张三","tags":["Go"]}`,
+			nil,
+		},
+		{
+			"null 转为空值",
+			`{"name":null,"active":null,"tags":null,"items":null,"date":null}`,
+			`{"active":false,"items":[],"name":"","tags":[]}`,
+			nil,
+		},
+		{
+			"数字和布尔转字符串",
+			`{"name":2019,"items":[{"title":true}]}`,
+			`{"active":false,"items":[{"bullets":[],"title":"true"}],"name":"2019","tags":[]}`,
+			nil,
+		},
+		{
+			"字符串数组拼接为字符串",
+			`{"name":["张","三"]}`,
+			`{"active":false,"items":[],"name":"张；三","tags":[]}`,
+			nil,
+		},
+		{
+			"分隔的字符串拆为数组",
+			`{"tags":"Go、MySQL, Redis","items":[{"bullets":"- 重构网关\n- 降低延迟"}]}`,
+			`{"active":false,"items":[{"bullets":["重构网关","降低延迟"],"title":""}],"name":"","tags":["Go","MySQL","Redis"]}`,
+			nil,
+		},
+		{
+			"单个对象包装为数组",
+			`{"items":{"title":"a"}}`,
+			`{"active":false,"items":[{"bullets":[],"title":"a"}],"name":"","tags":[]}`,
+			nil,
+		},
+		{
+			"字符串布尔值",
+			`{"active":" TRUE "}`,
+			`{"active":true,"items":[],"name":"","tags":[]}`,
+			nil,
+		},
+		{
+			"忽略时间字段、未知字段和不序列化的字段",
+			`{"created_at":"昨天","unknown":1,"Internal":"x","-":"x"}`,
+			`{"active":false,"items":[],"name":"","tags":[]}`,
+			nil,
+		},
+		{
+			"自定义解码的类型接受数字",
+			`{"date":2019}`,
+			`{"active":false,"date":2019,"items":[],"name":"","tags":[]}`,
+			nil,
+		},
+		{
+			"无法修正的问题",
+			`{"name":{"first":"张"},"active":1,"tags":[{"name":"Go"}],"items":["a"],"date":{"year":2019}}`,
+			`{"active":false,"items":[{"title":"","bullets":null}],"name":"","tags":[""]}`,
+			[]string{
+				"name: 应为字符串，实际为对象",
+				"active: 应为布尔值，实际为数字",
+				"tags[0]: 应为字符串，实际为对象",
+				"items[0]: 应为对象，实际为字符串",
+				"date: 应为字符串，实际为对象",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dec := json.NewDecoder(strings.NewReader(tt.in))
+			dec.UseNumber()
+			var raw any
+			if err := dec.Decode(&raw); err != nil {
+				t.Fatalf("Decode: %v", err)
+			}
+
+			var problems []string
+			got, err := json.Marshal(coerce(raw, reflect.TypeOf(sample{}), "", &problems))
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("coerce = %s\n期望 %s", got, tt.want)
+			}
+			if !reflect.DeepEqual(problems, tt.problems) {
+				t.Errorf("problems = %q\n期望 %q", problems, tt.problems)
+			}
+		})
+	}
+}
+
+func TestDecodeOutput(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		problems []string // 为空表示解码成功
+	}{
+		{"修正后解码", "```json\n{\"name\":\"张三\",\"tags\":\"Go，MySQL\"}\n```", nil},
+		{"缺少必填字段", `{"name":"  ","tags":[]}`, []string{"name: 不能为空"}},
+		{"没有 JSON", "无法处理", []string{errNoJSON.Error()}},
+		{"输出被截断", `{"name":"张三","tags":["Go"`, []string{"输出不完整：JSON 对象未闭合，可能超出了长度限制，请输出完整且更精简的 JSON"}},
+		{"条目类型错误", `{"name":"张三","items":[1]}`, []string{"items[0]: 应为对象，实际为数字"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := sample{Internal: "上一次的结果", Tags: []string{"旧"}}
+			err := decodeOutput(tt.content, &target, "name")
+
+			var outErr *OutputError
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("decodeOutput: %v", err)
+				}
+				if target.Name != "张三" || !reflect.DeepEqual(target.Tags, []string{"Go", "MySQL"}) || target.Internal != "" {
+					t.Errorf("target = %+v", target)
+				}
+				return
+			}
+			if !errors.As(err, &outErr) || !reflect.DeepEqual(outErr.Problems, tt.problems) {
+				t.Errorf("decodeOutput = %v，期望问题 %q", err, tt.problems)
+			}
+		})
+	}
+}
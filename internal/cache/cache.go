@@ -0,0 +1,48 @@
+package cache
+
+import (
+	"ResumeBuilder/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 缓存后端，对应配置 cache.backend
+const (
+	BackendRedis  = "redis"  // Redis，多实例共享（默认）
+	BackendMemory = "memory" // 进程内 LRU，只适用于单实例部署和本地开发
+	BackendNone   = "none"   // 不缓存，每次读取数据库
+)
+
+// ErrMiss 键不存在或已过期
+var ErrMiss = errors.New("缓存未命中")
+
+// Cache 键值缓存，值为字符串，实现需并发安全。
+// 除 ErrMiss 外的错误表示缓存不可用，调用方应直接读写数据库
+type Cache interface {
+	// Get 读取 key，不存在或已过期时返回 ErrMiss
+	Get(ctx context.Context, key string) (string, error)
+	// Set 写入 key，覆盖已有值
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// SetNX 仅当 key 不存在时写入，已存在时不做任何事
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) error
+}
+
+// New 按配置创建缓存，client 仅在使用 Redis 时需要
+func New(cfg config.CacheConfig, client *redis.Client) (Cache, error) {
+	switch cfg.Backend {
+	case BackendRedis:
+		if client == nil {
+			return nil, errors.New("Redis 缓存需要配置 REDIS_ADDR")
+		}
+		return NewRedis(client, cfg.Retry), nil
+	case BackendMemory:
+		return NewMemory(cfg.MaxEntries), nil
+	case BackendNone:
+		return Noop{}, nil
+	}
+	return nil, fmt.Errorf("不支持的缓存后端: %s", cfg.Backend)
+}
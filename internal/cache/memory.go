@@ -0,0 +1,96 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// Memory 进程内 LRU 缓存：条目数超过上限时淘汰最久未使用的条目，过期条目在读取时删除。
+// 各实例的缓存互不可见，多实例部署时一个实例的写入不能使其他实例的缓存失效
+type Memory struct {
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // 最近使用的在前
+}
+
+type memoryEntry struct {
+	key       string
+	value     string
+	expiresAt time.Time
+}
+
+// NewMemory 创建最多保存 maxEntries 个条目的缓存，maxEntries 不大于 0 时不限制条目数
+func NewMemory(maxEntries int) *Memory {
+	return &Memory{
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (c *Memory) Get(_ context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.lookup(key, time.Now())
+	if !ok {
+		return "", ErrMiss
+	}
+	c.order.MoveToFront(e)
+	return e.Value.(*memoryEntry).value, nil
+}
+
+func (c *Memory) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value, ttl)
+	return nil
+}
+
+func (c *Memory) SetNX(_ context.Context, key, value string, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.lookup(key, time.Now()); !ok {
+		c.set(key, value, ttl)
+	}
+	return nil
+}
+
+// lookup 查找未过期的条目，已过期的条目被删除
+func (c *Memory) lookup(key string, now time.Time) (*list.Element, bool) {
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if now.After(e.Value.(*memoryEntry).expiresAt) {
+		c.remove(e)
+		return nil, false
+	}
+	return e, true
+}
+
+func (c *Memory) set(key, value string, ttl time.Duration) {
+	expiresAt := time.Now().Add(ttl)
+	if e, ok := c.entries[key]; ok {
+		entry := e.Value.(*memoryEntry)
+		entry.value, entry.expiresAt = value, expiresAt
+		c.order.MoveToFront(e)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&memoryEntry{key: key, value: value, expiresAt: expiresAt})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Memory) remove(e *list.Element) {
+	c.order.Remove(e)
+	delete(c.entries, e.Value.(*memoryEntry).key)
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"ResumeBuilder/internal/config"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// op 对缓存的一次操作：set/setnx 写入 value，get 期望读到 value（为空表示未命中）
+type op struct {
+	kind  string
+	key   string
+	value string
+	ttl   time.Duration
+	sleep time.Duration // 操作前等待的时间
+}
+
+// apply 依次执行 ops，get 的结果与期望不符时报错
+func apply(t *testing.T, c Cache, ops []op) {
+	t.Helper()
+	ctx := context.Background()
+	for i, o := range ops {
+		time.Sleep(o.sleep)
+		switch o.kind {
+		case "set":
+			if err := c.Set(ctx, o.key, o.value, o.ttl); err != nil {
+				t.Fatalf("第 %d 步 Set(%q): %v", i, o.key, err)
+			}
+		case "setnx":
+			if err := c.SetNX(ctx, o.key, o.value, o.ttl); err != nil {
+				t.Fatalf("第 %d 步 SetNX(%q): %v", i, o.key, err)
+			}
+		case "get":
+			got, err := c.Get(ctx, o.key)
+			if o.value == "" {
+				if !errors.Is(err, ErrMiss) {
+					t.Fatalf("第 %d 步 Get(%q) = %q, %v，期望未命中", i, o.key, got, err)
+				}
+			} else if err != nil || got != o.value {
+				t.Fatalf("第 %d 步 Get(%q) = %q, %v，期望 %q", i, o.key, got, err, o.value)
+			}
+		}
+	}
+}
+
+func TestMemory(t *testing.T) {
+	const ttl = time.Minute
+	const short = 20 * time.Millisecond
+
+	tests := []struct {
+		name       string
+		maxEntries int
+		ops        []op
+	}{
+		{"未命中", 2, []op{
+			{kind: "get", key: "a"},
+		}},
+		{"覆盖已有值", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: ttl},
+			{kind: "set", key: "a", value: "2", ttl: ttl},
+			{kind: "get", key: "a", value: "2"},
+		}},
+		{"超过上限时淘汰最久未写入的条目", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: ttl},
+			{kind: "set", key: "b", value: "2", ttl: ttl},
+			{kind: "set", key: "c", value: "3", ttl: ttl},
+			{kind: "get", key: "a"},
+			{kind: "get", key: "b", value: "2"},
+			{kind: "get", key: "c", value: "3"},
+		}},
+		{"读取使条目变为最近使用", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: ttl},
+			{kind: "set", key: "b", value: "2", ttl: ttl},
+			{kind: "get", key: "a", value: "1"},
+			{kind: "set", key: "c", value: "3", ttl: ttl},
+			{kind: "get", key: "b"},
+			{kind: "get", key: "a", value: "1"},
+		}},
+		{"覆盖使条目变为最近使用", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: ttl},
+			{kind: "set", key: "b", value: "2", ttl: ttl},
+			{kind: "set", key: "a", value: "3", ttl: ttl},
+			{kind: "set", key: "c", value: "4", ttl: ttl},
+			{kind: "get", key: "b"},
+			{kind: "get", key: "a", value: "3"},
+		}},
+		{"不限制条目数", 0, []op{
+			{kind: "set", key: "a", value: "1", ttl: ttl},
+			{kind: "set", key: "b", value: "2", ttl: ttl},
+			{kind: "set", key: "c", value: "3", ttl: ttl},
+			{kind: "get", key: "a", value: "1"},
+		}},
+		{"过期", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: short},
+			{kind: "set", key: "b", value: "2", ttl: ttl},
+			{kind: "get", key: "a", value: "1"},
+			{kind: "get", key: "a", sleep: 2 * short},
+			{kind: "get", key: "b", value: "2"},
+		}},
+		{"覆盖时重新计算过期时间", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: short},
+			{kind: "set", key: "a", value: "2", ttl: ttl},
+			{kind: "get", key: "a", value: "2", sleep: 2 * short},
+		}},
+		{"SetNX 不覆盖已有值", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: ttl},
+			{kind: "setnx", key: "a", value: "2", ttl: ttl},
+			{kind: "get", key: "a", value: "1"},
+			{kind: "setnx", key: "b", value: "3", ttl: ttl},
+			{kind: "get", key: "b", value: "3"},
+		}},
+		{"SetNX 覆盖已过期的值", 2, []op{
+			{kind: "set", key: "a", value: "1", ttl: short},
+			{kind: "setnx", key: "a", value: "2", ttl: ttl, sleep: 2 * short},
+			{kind: "get", key: "a", value: "2"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMemory(tt.maxEntries)
+			apply(t, c, tt.ops)
+			if tt.maxEntries > 0 && c.order.Len() > tt.maxEntries {
+				t.Errorf("缓存了 %d 个条目，超过上限 %d", c.order.Len(), tt.maxEntries)
+			}
+			if len(c.entries) != c.order.Len() {
+				t.Errorf("索引中有 %d 个条目，链表中有 %d 个", len(c.entries), c.order.Len())
+			}
+		})
+	}
+}
+
+func TestNoop(t *testing.T) {
+	apply(t, Noop{}, []op{
+		{kind: "get", key: "a"},
+		{kind: "set", key: "a", value: "1", ttl: time.Minute},
+		{kind: "setnx", key: "b", value: "2", ttl: time.Minute},
+		{kind: "get", key: "a"},
+		{kind: "get", key: "b"},
+	})
+}
+
+func TestNew(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:0"})
+	defer client.Close()
+
+	tests := []struct {
+		backend string
+		client  *redis.Client
+		want    string // 返回的类型，为空表示应返回错误
+	}{
+		{BackendRedis, client, "*cache.Redis"},
+		{BackendRedis, nil, ""},
+		{BackendMemory, nil, "*cache.Memory"},
+		{BackendNone, nil, "cache.Noop"},
+		{"memcached", nil, ""},
+	}
+	for _, tt := range tests {
+		c, err := New(config.CacheConfig{Backend: tt.backend}, tt.client)
+		got := ""
+		if err == nil {
+			got = fmt.Sprintf("%T", c)
+		}
+		if got != tt.want {
+			t.Errorf("New(%q) = %s, %v，期望 %q", tt.backend, got, err, tt.want)
+		}
+	}
+}
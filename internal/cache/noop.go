@@ -0,0 +1,15 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Noop 不缓存任何内容，读取总是未命中
+type Noop struct{}
+
+func (Noop) Get(context.Context, string) (string, error) { return "", ErrMiss }
+
+func (Noop) Set(context.Context, string, string, time.Duration) error { return nil }
+
+func (Noop) SetNX(context.Context, string, string, time.Duration) error { return nil }
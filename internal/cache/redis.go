@@ -0,0 +1,67 @@
+package cache
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/resilience"
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis 基于 Redis 的缓存。Redis 连续出错时熔断，熔断期间直接返回错误而不再等待连接超时，
+// 调用方随之降级为只读写数据库，冷却后自动试探恢复
+type Redis struct {
+	client *redis.Client
+	rc     *resilience.Client
+}
+
+// NewRedis 创建 Redis 缓存，retry 为重试与熔断配置
+func NewRedis(client *redis.Client, retry config.ResilienceConfig) *Redis {
+	return &Redis{client: client, rc: resilience.New("redis-cache", retry)}
+}
+
+func (c *Redis) Get(ctx context.Context, key string) (string, error) {
+	var val string
+	miss := false
+	err := c.do(ctx, func(ctx context.Context) error {
+		var err error
+		val, err = c.client.Get(ctx, key).Result()
+		if errors.Is(err, redis.Nil) {
+			// 未命中不是故障，不计入熔断
+			miss = true
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	if miss {
+		return "", ErrMiss
+	}
+	return val, nil
+}
+
+func (c *Redis) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.Set(ctx, key, value, ttl).Err()
+	})
+}
+
+func (c *Redis) SetNX(ctx context.Context, key, value string, ttl time.Duration) error {
+	return c.do(ctx, func(ctx context.Context) error {
+		return c.client.SetNX(ctx, key, value, ttl).Err()
+	})
+}
+
+// do 执行 Redis 命令，错误包装为 *resilience.Error 以便熔断器识别
+func (c *Redis) do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return c.rc.Do(ctx, func(ctx context.Context) error {
+		if err := fn(ctx); err != nil {
+			return &resilience.Error{Upstream: "redis", Err: err}
+		}
+		return nil
+	})
+}
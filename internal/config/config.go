@@ -0,0 +1,512 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+)
+
+// defaultConfigFile 未指定 CONFIG_FILE 时尝试读取的配置文件
+const defaultConfigFile = "config.yaml"
+
+// defaultModel 未配置时默认使用的模型
+const defaultModel = "deepseek-r1-250528"
+
+// Config 应用的全部配置，启动时加载一次并注入到各层构造函数
+type Config struct {
+	HTTP   HTTPConfig   `yaml:"http"`
+	DB     DBConfig     `yaml:"db"`
+	Redis  RedisConfig  `yaml:"redis"`
+	Cache  CacheConfig  `yaml:"cache"`
+	AI     AIConfig     `yaml:"ai"`
+	GitHub GitHubConfig `yaml:"github"`
+	Jobs   JobConfig    `yaml:"jobs"`
+	Trash  TrashConfig  `yaml:"trash"`
+
+	Validation ValidationConfig `yaml:"validation"`
+}
+
+// HTTPConfig HTTP服务配置
+type HTTPConfig struct {
+	Addr   string `yaml:"addr"`    // 监听地址，如 :8080
+	WebDir string `yaml:"web_dir"` // 前端静态文件目录
+
+	// ShutdownTimeout 收到退出信号后等待进行中的请求和任务完成的最长时间
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	RequestTimeout time.Duration `yaml:"request_timeout"` // 普通接口的处理时限
+	AITimeout      time.Duration `yaml:"ai_timeout"`      // 调用大模型的接口（生成、GitHub分析）的处理时限
+	// Timeouts 按路由覆盖处理时限，键为 "方法 路由"，如 "POST /api/resume/:userID/generate"
+	Timeouts map[string]time.Duration `yaml:"timeouts"`
+
+	// RequireIfMatch 修改简历的接口是否必须携带 If-Match 请求头（值为读取简历时返回的 ETag），
+	// 缺少时返回 428；关闭时不带 If-Match 的请求直接覆盖
+	RequireIfMatch bool `yaml:"require_if_match"`
+
+	// DebugVars 是否提供 /debug/vars 接口（简历缓存的命中、未命中、加载和错误次数），默认关闭
+	DebugVars bool `yaml:"debug_vars"`
+}
+
+// DBConfig 数据库配置
+type DBConfig struct {
+	// Driver 存储实现：mysql（默认）、sqlite（单机部署，DSN 为数据库文件路径）、
+	// memory（进程内存储，重启后数据丢失，仅用于本地演示和测试）
+	Driver string `yaml:"driver"`
+	DSN    string `yaml:"dsn"`
+	// AutoMigrate 启动时自动执行未执行的数据库迁移；关闭时数据库结构落后则拒绝启动，需先运行 migrate up
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+// RedisConfig Redis配置
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+// CacheConfig 简历缓存配置
+type CacheConfig struct {
+	// Backend 缓存后端：redis（默认，多实例共享）、memory（进程内 LRU，仅限单实例部署）、none（不缓存）
+	Backend string `yaml:"backend"`
+	// MaxEntries memory 后端最多缓存的条目数，0 表示不限制
+	MaxEntries int `yaml:"max_entries"`
+
+	TTL time.Duration `yaml:"ttl"`
+	// TTLJitter 每次写入缓存时在 TTL 上随机增加 0~TTLJitter，避免同时写入的缓存同时过期
+	TTLJitter time.Duration `yaml:"ttl_jitter"`
+	// NegativeTTL 简历不存在、用户没有简历时缓存该结果的时间，0 表示不缓存
+	NegativeTTL time.Duration `yaml:"negative_ttl"`
+	// TombstoneTTL 写入后缓存键保持失效（不回填）的时间，应大于一次数据库读取的耗时，
+	// 防止写入前读到旧数据的并发请求把旧内容写回缓存
+	TombstoneTTL time.Duration `yaml:"tombstone_ttl"`
+
+	// Retry redis 后端的重试与熔断：Redis 连续出错时熔断，期间直接读写数据库，不再等待连接超时
+	Retry ResilienceConfig `yaml:"retry"`
+}
+
+// TaskConfig 单个任务（简历解析、GitHub项目分析）的模型调用参数
+type TaskConfig struct {
+	Model       string        `yaml:"model"`
+	Temperature *float32      `yaml:"temperature"` // 为空时使用模型默认值
+	MaxTokens   int           `yaml:"max_tokens"`  // 0 表示不限制
+	Timeout     time.Duration `yaml:"timeout"`     // 0 表示使用全局超时
+}
+
+// ResilienceConfig 调用上游服务（大模型、GitHub）的重试与熔断配置
+type ResilienceConfig struct {
+	MaxAttempts      int           `yaml:"max_attempts"`      // 最多尝试次数（含首次），1 表示不重试
+	BaseDelay        time.Duration `yaml:"base_delay"`        // 首次重试前的等待时间，之后逐次翻倍并加随机抖动
+	MaxDelay         time.Duration `yaml:"max_delay"`         // 单次等待上限，上游要求等待更久时不再重试
+	BreakerThreshold int           `yaml:"breaker_threshold"` // 连续失败多少次后熔断，0 表示不熔断
+	BreakerCooldown  time.Duration `yaml:"breaker_cooldown"`  // 熔断持续时间，之后放行一次试探请求
+}
+
+// AIConfig 大模型配置
+type AIConfig struct {
+	Provider string        `yaml:"provider"` // ark / openai / fake
+	APIKey   string        `yaml:"api_key"`
+	BaseURL  string        `yaml:"base_url"`
+	Timeout  time.Duration `yaml:"timeout"` // 单次模型调用的默认超时
+
+	Resume TaskConfig `yaml:"resume"` // 完整简历解析，建议使用能力更强的模型
+	GitHub TaskConfig `yaml:"github"` // GitHub项目分析，可使用更便宜的模型
+
+	Retry ResilienceConfig `yaml:"retry"`
+}
+
+// GitHubConfig GitHub访问配置
+type GitHubConfig struct {
+	Token string `yaml:"token"` // 访问公开仓库时可留空
+
+	Retry ResilienceConfig `yaml:"retry"`
+}
+
+// JobConfig 异步任务队列配置
+type JobConfig struct {
+	// Enabled 是否启用异步任务队列（依赖 Redis），默认关闭；关闭时不注册 /api/jobs 相关接口
+	Enabled bool `yaml:"enabled"`
+
+	Workers      int           `yaml:"workers"`       // 并发处理任务的 worker 数量
+	MaxAttempts  int           `yaml:"max_attempts"`  // 单个任务最多执行次数（含首次）
+	RetryBackoff time.Duration `yaml:"retry_backoff"` // 首次重试前的等待时间，之后逐次翻倍
+	Timeout      time.Duration `yaml:"timeout"`       // 单次执行超时
+	ResultTTL    time.Duration `yaml:"result_ttl"`    // 任务状态与结果的保留时长
+}
+
+// TrashConfig 回收站配置
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`      // 删除的简历在回收站中保留的时长，之后被永久删除；0 表示永久保留
+	PurgeInterval time.Duration `yaml:"purge_interval"` // 清理回收站的间隔
+}
+
+// ValidationConfig 保存简历和AI生成结果时的内容限制，0 表示不限制
+type ValidationConfig struct {
+	MaxItems       int `yaml:"max_items"`        // 基本信息、教育、工作、项目等章节的最多条目数
+	MaxListItems   int `yaml:"max_list_items"`   // 成就、亮点、技术栈等列表的最多条数
+	MaxSkills      int `yaml:"max_skills"`       // 技能最多条数
+	MaxFieldLength int `yaml:"max_field_length"` // 姓名、公司、职位、技能等短字段的最大字符数
+	MaxTextLength  int `yaml:"max_text_length"`  // 描述、成就、亮点等长文本的最大字符数
+}
+
+// Default 返回默认配置
+func Default() *Config {
+	return &Config{
+		HTTP: HTTPConfig{
+			Addr:            ":8080",
+			WebDir:          "./web",
+			ShutdownTimeout: 30 * time.Second,
+			RequestTimeout:  30 * time.Second,
+			AITimeout:       10 * time.Minute,
+		},
+		DB: DBConfig{
+			Driver: "mysql",
+		},
+		Redis: RedisConfig{
+			Addr: "127.0.0.1:6379",
+		},
+		Cache: CacheConfig{
+			Backend:      "redis",
+			MaxEntries:   10000,
+			TTL:          10 * time.Minute,
+			TTLJitter:    time.Minute,
+			NegativeTTL:  30 * time.Second,
+			TombstoneTTL: 5 * time.Second,
+			Retry: ResilienceConfig{
+				MaxAttempts:      1,
+				BreakerThreshold: 3,
+				BreakerCooldown:  10 * time.Second,
+			},
+		},
+		AI: AIConfig{
+			Provider: "ark",
+			Timeout:  5 * time.Minute,
+			Resume:   TaskConfig{Model: defaultModel},
+			GitHub:   TaskConfig{Model: defaultModel},
+			Retry: ResilienceConfig{
+				MaxAttempts:      3,
+				BaseDelay:        time.Second,
+				MaxDelay:         20 * time.Second,
+				BreakerThreshold: 5,
+				BreakerCooldown:  30 * time.Second,
+			},
+		},
+		GitHub: GitHubConfig{
+			Retry: ResilienceConfig{
+				MaxAttempts:      3,
+				BaseDelay:        500 * time.Millisecond,
+				MaxDelay:         10 * time.Second,
+				BreakerThreshold: 5,
+				BreakerCooldown:  30 * time.Second,
+			},
+		},
+		Jobs: JobConfig{
+			Workers:      2,
+			MaxAttempts:  3,
+			RetryBackoff: 5 * time.Second,
+			Timeout:      10 * time.Minute,
+			ResultTTL:    24 * time.Hour,
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Validation: ValidationConfig{
+			MaxItems:       50,
+			MaxListItems:   30,
+			MaxSkills:      100,
+			MaxFieldLength: 200,
+			MaxTextLength:  5000,
+		},
+	}
+}
+
+// Load 加载配置，优先级：默认值 < YAML文件 < 环境变量（含 .env 文件）
+// YAML 文件路径由 CONFIG_FILE 指定，未指定时若当前目录存在 config.yaml 则读取
+// 所有缺失或非法的配置项会合并在一个错误中一次性返回
+func Load() (*Config, error) {
+	cfg, errs := load()
+	errs = append(errs, cfg.Validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// LoadDB 按与 Load 相同的来源加载配置，但只校验数据库配置，供不启动服务的 migrate 子命令使用
+func LoadDB() (*DBConfig, error) {
+	cfg, errs := load()
+	errs = append(errs, cfg.DB.validate())
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return &cfg.DB, nil
+}
+
+// load 读取默认值、配置文件和环境变量，返回未经校验的配置和解析错误
+func load() (*Config, []error) {
+	// .env 不覆盖已存在的系统环境变量；文件不存在时忽略
+	cfg := Default()
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return cfg, []error{fmt.Errorf("读取.env文件失败: %w", err)}
+	}
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(defaultConfigFile); err == nil {
+			path = defaultConfigFile
+		}
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, []error{fmt.Errorf("读取配置文件失败: %w", err)}
+		}
+		if err := yaml.Unmarshal(data, cfg); err != nil {
+			return cfg, []error{fmt.Errorf("解析配置文件 %s 失败: %w", path, err)}
+		}
+	}
+
+	return cfg, cfg.loadEnv()
+}
+
+// loadEnv 用环境变量覆盖配置，返回所有解析错误
+func (c *Config) loadEnv() []error {
+	setString(&c.HTTP.Addr, "HTTP_ADDR")
+	setString(&c.HTTP.WebDir, "WEB_DIR")
+	setString(&c.DB.Driver, "DB_DRIVER")
+	setString(&c.DB.DSN, "DB_URL")
+	setString(&c.Redis.Addr, "REDIS_ADDR")
+	setString(&c.Redis.Password, "REDIS_PASSWORD")
+	setString(&c.Cache.Backend, "CACHE_BACKEND")
+	setString(&c.GitHub.Token, "GITHUB_TOKEN")
+	setString(&c.AI.Provider, "AI_PROVIDER")
+	setString(&c.AI.APIKey, "apiKey")
+	setString(&c.AI.BaseURL, "AI_BASE_URL")
+
+	errs := []error{
+		setDuration(&c.HTTP.ShutdownTimeout, "SHUTDOWN_TIMEOUT"),
+		setDuration(&c.HTTP.RequestTimeout, "HTTP_REQUEST_TIMEOUT"),
+		setDuration(&c.HTTP.AITimeout, "HTTP_AI_TIMEOUT"),
+		setBool(&c.HTTP.RequireIfMatch, "HTTP_REQUIRE_IF_MATCH"),
+		setBool(&c.HTTP.DebugVars, "HTTP_DEBUG_VARS"),
+		setBool(&c.DB.AutoMigrate, "DB_AUTO_MIGRATE"),
+		setInt(&c.Redis.DB, "REDIS_DB"),
+		setInt(&c.Cache.MaxEntries, "CACHE_MAX_ENTRIES"),
+		setDuration(&c.Cache.TTL, "CACHE_TTL"),
+		setDuration(&c.Cache.TTLJitter, "CACHE_TTL_JITTER"),
+		setDuration(&c.Cache.NegativeTTL, "CACHE_NEGATIVE_TTL"),
+		setDuration(&c.Cache.TombstoneTTL, "CACHE_TOMBSTONE_TTL"),
+		setDuration(&c.AI.Timeout, "AI_TIMEOUT"),
+		setBool(&c.Jobs.Enabled, "JOB_ENABLED"),
+		setInt(&c.Jobs.Workers, "JOB_WORKERS"),
+		setInt(&c.Jobs.MaxAttempts, "JOB_MAX_ATTEMPTS"),
+		setDuration(&c.Jobs.RetryBackoff, "JOB_RETRY_BACKOFF"),
+		setDuration(&c.Jobs.Timeout, "JOB_TIMEOUT"),
+		setDuration(&c.Jobs.ResultTTL, "JOB_RESULT_TTL"),
+		setDuration(&c.Trash.Retention, "TRASH_RETENTION"),
+		setDuration(&c.Trash.PurgeInterval, "TRASH_PURGE_INTERVAL"),
+		setInt(&c.Validation.MaxItems, "VALIDATION_MAX_ITEMS"),
+		setInt(&c.Validation.MaxListItems, "VALIDATION_MAX_LIST_ITEMS"),
+		setInt(&c.Validation.MaxSkills, "VALIDATION_MAX_SKILLS"),
+		setInt(&c.Validation.MaxFieldLength, "VALIDATION_MAX_FIELD_LENGTH"),
+		setInt(&c.Validation.MaxTextLength, "VALIDATION_MAX_TEXT_LENGTH"),
+	}
+	errs = append(errs, loadTaskEnv(&c.AI.Resume, "AI_RESUME_")...)
+	errs = append(errs, loadTaskEnv(&c.AI.GitHub, "AI_GITHUB_")...)
+	errs = append(errs, loadRetryEnv(&c.AI.Retry, "AI_")...)
+	errs = append(errs, loadRetryEnv(&c.GitHub.Retry, "GITHUB_")...)
+	errs = append(errs, loadRetryEnv(&c.Cache.Retry, "CACHE_")...)
+	return errs
+}
+
+// Validate 校验配置，一次性返回所有错误
+func (c *Config) Validate() error {
+	var errs []error
+	if c.HTTP.Addr == "" {
+		errs = append(errs, errors.New("HTTP_ADDR: 监听地址不能为空"))
+	}
+	if c.HTTP.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("SHUTDOWN_TIMEOUT: 必须大于0"))
+	}
+	if c.HTTP.RequestTimeout <= 0 {
+		errs = append(errs, errors.New("HTTP_REQUEST_TIMEOUT: 必须大于0"))
+	}
+	if c.HTTP.AITimeout <= 0 {
+		errs = append(errs, errors.New("HTTP_AI_TIMEOUT: 必须大于0"))
+	}
+	for route, d := range c.HTTP.Timeouts {
+		if d <= 0 {
+			errs = append(errs, fmt.Errorf("http.timeouts[%s]: 必须大于0", route))
+		}
+	}
+	errs = append(errs, c.DB.validate())
+	switch c.Cache.Backend {
+	case "redis", "memory", "none":
+	default:
+		errs = append(errs, fmt.Errorf("CACHE_BACKEND: 不支持的缓存后端 %q，可选 redis / memory / none", c.Cache.Backend))
+	}
+	if c.Redis.Addr == "" && c.NeedsRedis() {
+		errs = append(errs, errors.New("REDIS_ADDR: 使用 Redis 缓存或启用任务队列时 Redis地址不能为空"))
+	}
+	if c.Redis.DB < 0 {
+		errs = append(errs, errors.New("REDIS_DB: 不能为负数"))
+	}
+	if c.Cache.MaxEntries < 0 {
+		errs = append(errs, errors.New("CACHE_MAX_ENTRIES: 不能为负数"))
+	}
+	if c.Cache.TTL <= 0 {
+		errs = append(errs, errors.New("CACHE_TTL: 缓存有效期必须大于0"))
+	}
+	if c.Cache.TTLJitter < 0 {
+		errs = append(errs, errors.New("CACHE_TTL_JITTER: 不能为负数"))
+	}
+	if c.Cache.NegativeTTL < 0 {
+		errs = append(errs, errors.New("CACHE_NEGATIVE_TTL: 不能为负数"))
+	}
+	if c.Cache.TombstoneTTL <= 0 {
+		errs = append(errs, errors.New("CACHE_TOMBSTONE_TTL: 必须大于0"))
+	}
+	if c.Jobs.Workers < 0 {
+		errs = append(errs, errors.New("JOB_WORKERS: 不能为负数"))
+	}
+	if c.Jobs.MaxAttempts < 1 {
+		errs = append(errs, errors.New("JOB_MAX_ATTEMPTS: 至少为1"))
+	}
+	if c.Jobs.RetryBackoff < 0 {
+		errs = append(errs, errors.New("JOB_RETRY_BACKOFF: 不能为负数"))
+	}
+	if c.Jobs.Timeout <= 0 {
+		errs = append(errs, errors.New("JOB_TIMEOUT: 必须大于0"))
+	}
+	if c.Jobs.ResultTTL <= 0 {
+		errs = append(errs, errors.New("JOB_RESULT_TTL: 必须大于0"))
+	}
+	if c.Trash.Retention < 0 {
+		errs = append(errs, errors.New("TRASH_RETENTION: 不能为负数"))
+	}
+	if c.Trash.Retention > 0 && c.Trash.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("TRASH_PURGE_INTERVAL: 必须大于0"))
+	}
+	errs = append(errs, c.AI.validate(), c.GitHub.Retry.validate("GITHUB_"), c.Cache.Retry.validate("CACHE_"), c.Validation.validate())
+	return errors.Join(errs...)
+}
+
+// NeedsRedis 是否需要连接 Redis：使用 Redis 缓存或启用了任务队列
+func (c *Config) NeedsRedis() bool {
+	return c.Cache.Backend == "redis" || c.Jobs.Enabled
+}
+
+func (d DBConfig) validate() error {
+	switch d.Driver {
+	case "mysql", "sqlite":
+		if d.DSN == "" {
+			return errors.New("DB_URL: 数据库连接串未配置")
+		}
+	case "memory":
+	default:
+		return fmt.Errorf("DB_DRIVER: 不支持的存储 %q，可选 mysql / sqlite / memory", d.Driver)
+	}
+	return nil
+}
+
+func (v ValidationConfig) validate() error {
+	limits := []struct {
+		key string
+		n   int
+	}{
+		{"VALIDATION_MAX_ITEMS", v.MaxItems},
+		{"VALIDATION_MAX_LIST_ITEMS", v.MaxListItems},
+		{"VALIDATION_MAX_SKILLS", v.MaxSkills},
+		{"VALIDATION_MAX_FIELD_LENGTH", v.MaxFieldLength},
+		{"VALIDATION_MAX_TEXT_LENGTH", v.MaxTextLength},
+	}
+
+	var errs []error
+	for _, l := range limits {
+		if l.n < 0 {
+			errs = append(errs, fmt.Errorf("%s: 不能为负数", l.key))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c AIConfig) validate() error {
+	var errs []error
+	switch strings.ToLower(c.Provider) {
+	case "ark", "openai":
+		if c.APIKey == "" {
+			errs = append(errs, errors.New("apiKey: 未配置AI服务的API Key"))
+		}
+	case "fake":
+	default:
+		errs = append(errs, fmt.Errorf("AI_PROVIDER: 不支持的AI提供方 %q", c.Provider))
+	}
+	if c.Timeout < 0 {
+		errs = append(errs, errors.New("AI_TIMEOUT: 不能为负数"))
+	}
+	errs = append(errs, c.Resume.validate("AI_RESUME_"), c.GitHub.validate("AI_GITHUB_"), c.Retry.validate("AI_"))
+	return errors.Join(errs...)
+}
+
+func (t TaskConfig) validate(prefix string) error {
+	var errs []error
+	if t.Model == "" {
+		errs = append(errs, fmt.Errorf("%sMODEL: 模型名称不能为空", prefix))
+	}
+	if t.Temperature != nil && (*t.Temperature < 0 || *t.Temperature > 2) {
+		errs = append(errs, fmt.Errorf("%sTEMPERATURE: 必须在 0~2 之间", prefix))
+	}
+	if t.MaxTokens < 0 {
+		errs = append(errs, fmt.Errorf("%sMAX_TOKENS: 不能为负数", prefix))
+	}
+	if t.Timeout < 0 {
+		errs = append(errs, fmt.Errorf("%sTIMEOUT: 不能为负数", prefix))
+	}
+	return errors.Join(errs...)
+}
+
+// loadTaskEnv 以 prefix 为前缀读取单个任务的环境变量
+func loadTaskEnv(t *TaskConfig, prefix string) []error {
+	setString(&t.Model, prefix+"MODEL")
+	return []error{
+		setFloat(&t.Temperature, prefix+"TEMPERATURE"),
+		setInt(&t.MaxTokens, prefix+"MAX_TOKENS"),
+		setDuration(&t.Timeout, prefix+"TIMEOUT"),
+	}
+}
+
+func (r ResilienceConfig) validate(prefix string) error {
+	var errs []error
+	if r.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("%sRETRY_MAX_ATTEMPTS: 至少为1", prefix))
+	}
+	if r.BaseDelay < 0 {
+		errs = append(errs, fmt.Errorf("%sRETRY_BASE_DELAY: 不能为负数", prefix))
+	}
+	if r.MaxDelay < r.BaseDelay {
+		errs = append(errs, fmt.Errorf("%sRETRY_MAX_DELAY: 不能小于 %sRETRY_BASE_DELAY", prefix, prefix))
+	}
+	if r.BreakerThreshold < 0 {
+		errs = append(errs, fmt.Errorf("%sBREAKER_THRESHOLD: 不能为负数", prefix))
+	}
+	if r.BreakerThreshold > 0 && r.BreakerCooldown <= 0 {
+		errs = append(errs, fmt.Errorf("%sBREAKER_COOLDOWN: 启用熔断时必须大于0", prefix))
+	}
+	return errors.Join(errs...)
+}
+
+// loadRetryEnv 以 prefix 为前缀读取重试与熔断的环境变量
+func loadRetryEnv(r *ResilienceConfig, prefix string) []error {
+	return []error{
+		setInt(&r.MaxAttempts, prefix+"RETRY_MAX_ATTEMPTS"),
+		setDuration(&r.BaseDelay, prefix+"RETRY_BASE_DELAY"),
+		setDuration(&r.MaxDelay, prefix+"RETRY_MAX_DELAY"),
+		setInt(&r.BreakerThreshold, prefix+"BREAKER_THRESHOLD"),
+		setDuration(&r.BreakerCooldown, prefix+"BREAKER_COOLDOWN"),
+	}
+}
@@ -0,0 +1,279 @@
+package config
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// isolate 在空的临时目录中加载配置，只使用 env 中的环境变量（以及 files 中的 .env、config.yaml 等文件）
+func isolate(t *testing.T, env map[string]string, files map[string]string) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	for name, content := range files {
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatalf("WriteFile(%s): %v", name, err)
+		}
+	}
+	t.Setenv("CONFIG_FILE", "")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+}
+
+// wantErrors 检查 err 包含 want 中的每一条错误信息
+func wantErrors(t *testing.T, err error, want []string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("err = nil，期望包含 %q", want)
+	}
+	for _, w := range want {
+		if !strings.Contains(err.Error(), w) {
+			t.Errorf("err = %v\n缺少 %q", err, w)
+		}
+	}
+}
+
+// minimal 不依赖外部服务即可通过校验的环境变量
+var minimal = map[string]string{
+	"DB_DRIVER":     "memory",
+	"CACHE_BACKEND": "memory",
+	"AI_PROVIDER":   "fake",
+}
+
+func with(extra map[string]string) map[string]string {
+	env := map[string]string{}
+	for k, v := range minimal {
+		env[k] = v
+	}
+	for k, v := range extra {
+		env[k] = v
+	}
+	return env
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		files map[string]string
+		check func(t *testing.T, c *Config)
+	}{
+		{"默认值", minimal, nil, func(t *testing.T, c *Config) {
+			if c.HTTP.Addr != ":8080" || c.Jobs.Enabled || c.HTTP.DebugVars || c.Jobs.Workers != 2 || c.AI.Resume.Model != defaultModel {
+				t.Errorf("c = %+v", c)
+			}
+		}},
+		{
+			"环境变量的格式",
+			with(map[string]string{
+				"HTTP_AI_TIMEOUT":       "90s",
+				"HTTP_REQUIRE_IF_MATCH": "true",
+				"CACHE_MAX_ENTRIES":     "500",
+				"AI_RESUME_TEMPERATURE": "0.3",
+				"AI_GITHUB_MODEL":       "gpt-4o-mini",
+				"CACHE_RETRY_MAX_DELAY": "2s",
+			}),
+			nil,
+			func(t *testing.T, c *Config) {
+				if c.HTTP.AITimeout != 90*time.Second || !c.HTTP.RequireIfMatch || c.Cache.MaxEntries != 500 ||
+					c.AI.Resume.Temperature == nil || *c.AI.Resume.Temperature != 0.3 ||
+					c.AI.GitHub.Model != "gpt-4o-mini" || c.AI.Resume.Model != defaultModel || c.Cache.Retry.MaxDelay != 2*time.Second {
+					t.Errorf("c = %+v", c)
+				}
+			},
+		},
+		{
+			"环境变量覆盖配置文件",
+			with(map[string]string{"HTTP_ADDR": ":7000"}),
+			map[string]string{"config.yaml": "http:\n  addr: \":9000\"\n  web_dir: ./dist\njobs:\n  workers: 4\n"},
+			func(t *testing.T, c *Config) {
+				if c.HTTP.Addr != ":7000" || c.HTTP.WebDir != "./dist" || c.Jobs.Workers != 4 || c.Jobs.MaxAttempts != 3 {
+					t.Errorf("c.HTTP = %+v, c.Jobs = %+v", c.HTTP, c.Jobs)
+				}
+			},
+		},
+		{
+			".env 不覆盖已有的环境变量",
+			with(map[string]string{"JOB_WORKERS": "8"}),
+			map[string]string{".env": "JOB_WORKERS=1\nJOB_ENABLED=true\nREDIS_ADDR=redis:6379\n"},
+			func(t *testing.T, c *Config) {
+				if c.Jobs.Workers != 8 || !c.Jobs.Enabled || c.Redis.Addr != "redis:6379" {
+					t.Errorf("c.Jobs = %+v, c.Redis = %+v", c.Jobs, c.Redis)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t, tt.env, tt.files)
+			c, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			tt.check(t, c)
+		})
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		env   map[string]string
+		files map[string]string
+		want  []string
+	}{
+		{
+			"格式错误与校验错误一次性返回",
+			map[string]string{
+				"DB_DRIVER":             "oracle",
+				"JOB_WORKERS":           "two",
+				"CACHE_TTL":             "10",
+				"HTTP_DEBUG_VARS":       "yes",
+				"AI_RESUME_TEMPERATURE": "hot",
+				"AI_GITHUB_TEMPERATURE": "3",
+			},
+			nil,
+			[]string{
+				`JOB_WORKERS: 不是有效的整数 "two"`,
+				`CACHE_TTL: 不是有效的时长（如 90s、2m） "10"`,
+				`HTTP_DEBUG_VARS: 不是有效的布尔值（true/false） "yes"`,
+				`AI_RESUME_TEMPERATURE: 不是有效的数字 "hot"`,
+				`DB_DRIVER: 不支持的存储 "oracle"`,
+				"apiKey: 未配置AI服务的API Key",
+				"AI_GITHUB_TEMPERATURE: 必须在 0~2 之间",
+			},
+		},
+		{
+			"指定的配置文件不存在",
+			with(map[string]string{"CONFIG_FILE": "missing.yaml"}),
+			nil,
+			[]string{"读取配置文件失败"},
+		},
+		{
+			"配置文件格式错误",
+			minimal,
+			map[string]string{"config.yaml": "http: [\n"},
+			[]string{"解析配置文件 config.yaml 失败"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t, tt.env, tt.files)
+			c, err := Load()
+			if c != nil {
+				t.Errorf("Load 失败时返回了配置 %+v", c)
+			}
+			wantErrors(t, err, tt.want)
+		})
+	}
+}
+
+func TestLoadDB(t *testing.T) {
+	// 只校验数据库配置，AI 等配置缺失不影响 migrate 子命令
+	isolate(t, map[string]string{"DB_DRIVER": "sqlite", "DB_URL": "resume.db", "AI_PROVIDER": "unknown"}, nil)
+	db, err := LoadDB()
+	if err != nil || db.Driver != "sqlite" || db.DSN != "resume.db" {
+		t.Fatalf("LoadDB = %+v, %v", db, err)
+	}
+
+	isolate(t, map[string]string{"DB_DRIVER": "sqlite", "DB_URL": "", "JOB_WORKERS": "x"}, nil)
+	_, err = LoadDB()
+	wantErrors(t, err, []string{"DB_URL: 数据库连接串未配置", "JOB_WORKERS: 不是有效的整数"})
+}
+
+func TestValidate(t *testing.T) {
+	valid := func() *Config {
+		c := Default()
+		c.DB.DSN = "root@tcp(127.0.0.1:3306)/resume"
+		c.AI.APIKey = "key"
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		change func(c *Config)
+		want   []string // 为空表示校验通过
+	}{
+		{"默认配置加上必填项", func(*Config) {}, nil},
+		{"内存存储不需要连接串", func(c *Config) { c.DB.Driver, c.DB.DSN = "memory", "" }, nil},
+		{"不使用 Redis 时可以不配置地址", func(c *Config) { c.Cache.Backend, c.Redis.Addr = "none", "" }, nil},
+		{
+			"多个错误一起返回",
+			func(c *Config) {
+				c.HTTP.Addr = ""
+				c.HTTP.Timeouts = map[string]time.Duration{"GET /api/resume/:userID": 0}
+				c.DB.DSN = ""
+				c.Cache.Backend = "memcached"
+				c.Jobs.MaxAttempts = 0
+				c.Validation.MaxSkills = -1
+			},
+			[]string{
+				"HTTP_ADDR: 监听地址不能为空",
+				"http.timeouts[GET /api/resume/:userID]: 必须大于0",
+				"DB_URL: 数据库连接串未配置",
+				`CACHE_BACKEND: 不支持的缓存后端 "memcached"`,
+				"JOB_MAX_ATTEMPTS: 至少为1",
+				"VALIDATION_MAX_SKILLS: 不能为负数",
+			},
+		},
+		{"Redis 缓存需要地址", func(c *Config) { c.Redis.Addr = "" }, []string{"REDIS_ADDR"}},
+		{
+			"任务队列需要 Redis 地址",
+			func(c *Config) { c.Cache.Backend, c.Jobs.Enabled, c.Redis.Addr = "memory", true, "" },
+			[]string{"REDIS_ADDR"},
+		},
+		{
+			"重试与熔断",
+			func(c *Config) {
+				c.AI.Retry.MaxDelay = time.Millisecond
+				c.GitHub.Retry.BreakerCooldown = 0
+				c.Cache.Retry.MaxAttempts = 0
+			},
+			[]string{
+				"AI_RETRY_MAX_DELAY: 不能小于 AI_RETRY_BASE_DELAY",
+				"GITHUB_BREAKER_COOLDOWN: 启用熔断时必须大于0",
+				"CACHE_RETRY_MAX_ATTEMPTS: 至少为1",
+			},
+		},
+		{"回收站保留时需要清理间隔", func(c *Config) { c.Trash.PurgeInterval = 0 }, []string{"TRASH_PURGE_INTERVAL"}},
+		{"永久保留时不需要清理间隔", func(c *Config) { c.Trash.Retention, c.Trash.PurgeInterval = 0, 0 }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := valid()
+			tt.change(c)
+			err := c.Validate()
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			wantErrors(t, err, tt.want)
+		})
+	}
+}
+
+func TestNeedsRedis(t *testing.T) {
+	tests := []struct {
+		backend string
+		jobs    bool
+		want    bool
+	}{
+		{"redis", false, true},
+		{"redis", true, true},
+		{"memory", false, false},
+		{"memory", true, true},
+		{"none", false, false},
+		{"none", true, true},
+	}
+	for _, tt := range tests {
+		c := Default()
+		c.Cache.Backend, c.Jobs.Enabled = tt.backend, tt.jobs
+		if got := c.NeedsRedis(); got != tt.want {
+			t.Errorf("NeedsRedis(backend=%s, jobs=%v) = %v，期望 %v", tt.backend, tt.jobs, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// 以下函数在环境变量存在时覆盖 dst，格式错误时返回带变量名的错误
+
+func setString(dst *string, key string) {
+	if v := os.Getenv(key); v != "" {
+		*dst = v
+	}
+}
+
+func setInt(dst *int, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s: 不是有效的整数 %q", key, v)
+	}
+	*dst = n
+	return nil
+}
+
+func setBool(dst *bool, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return fmt.Errorf("%s: 不是有效的布尔值（true/false） %q", key, v)
+	}
+	*dst = b
+	return nil
+}
+
+func setFloat(dst **float32, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(v, 32)
+	if err != nil {
+		return fmt.Errorf("%s: 不是有效的数字 %q", key, v)
+	}
+	f32 := float32(f)
+	*dst = &f32
+	return nil
+}
+
+func setDuration(dst *time.Duration, key string) error {
+	v := os.Getenv(key)
+	if v == "" {
+		return nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return fmt.Errorf("%s: 不是有效的时长（如 90s、2m） %q", key, v)
+	}
+	*dst = d
+	return nil
+}
@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/agent"
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/job"
+	"ResumeBuilder/internal/resilience"
+	"ResumeBuilder/internal/service"
+	"ResumeBuilder/internal/validation"
+	"context"
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// 错误响应中的错误码，前端应根据错误码而不是错误信息判断错误类型
+const (
+	CodeInvalidArgument = "invalid_argument"      // 400 参数或简历内容校验失败，details 中为具体字段
+	CodeNotFound        = "not_found"             // 404 简历、条目、修订或任务不存在
+	CodeConflict        = "conflict"              // 409 项目重复、任务已结束、简历被频繁并发修改等
+	CodePrecondition    = "precondition_failed"   // 412 If-Match 与简历当前版本不一致
+	CodeIfMatchRequired = "precondition_required" // 428 缺少 If-Match
+	CodeRateLimited     = "rate_limited"          // 429 被大模型服务或GitHub限流
+	CodeAIError         = "ai_error"              // 502/503 大模型调用失败或输出无法使用
+	CodeGitHubError     = "github_error"          // 502/503 GitHub不可用
+	CodeUnavailable     = "unavailable"           // 503 其他上游暂时不可用
+	CodeTimeout         = "timeout"               // 504 请求超过处理时限
+	CodeInternal        = "internal"              // 500 服务器内部错误
+)
+
+// RequestIDHeader 请求ID的请求头与响应头，客户端未提供时由服务端生成
+const RequestIDHeader = "X-Request-ID"
+
+const requestIDKey = "request_id"
+
+// ErrorResponse 统一的错误响应
+type ErrorResponse struct {
+	Error     string              `json:"error"` // 错误信息，可直接展示给用户
+	Code      string              `json:"code"`
+	Details   []domain.FieldError `json:"details,omitempty"`
+	RequestID string              `json:"request_id,omitempty"`
+}
+
+// RequestID 为每个请求分配请求ID，写入响应头并在错误响应和日志中携带
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = uuid.NewString()
+		}
+		c.Set(requestIDKey, id)
+		c.Header(RequestIDHeader, id)
+		c.Next()
+	}
+}
+
+// ErrorHandler 处理器通过 c.Error 记录错误后返回，由该中间件统一映射状态码并输出错误响应
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		status, resp := errorResponse(c, c.Errors.Last().Err)
+		c.JSON(status, resp)
+	}
+}
+
+// errorResponse 将错误映射为HTTP状态码和错误响应。上游限流或不可用时设置 Retry-After，
+// 未识别的错误只记录日志，不向客户端暴露内部信息
+func errorResponse(c *gin.Context, err error) (int, ErrorResponse) {
+	resp := ErrorResponse{Error: err.Error(), RequestID: c.GetString(requestIDKey)}
+
+	var invalid *domain.ValidationError
+	var upErr *resilience.Error
+	switch {
+	case errors.As(err, &invalid):
+		// 校验提示按 Accept-Language 返回中文或英文
+		invalid = validation.Localize(invalid, validation.ParseLang(c.GetHeader("Accept-Language")))
+		resp.Code, resp.Error, resp.Details = CodeInvalidArgument, invalid.Error(), invalid.Fields
+		return http.StatusBadRequest, resp
+
+	case errors.Is(err, dao.ErrNotFound), errors.Is(err, dao.ErrRevisionNotFound), errors.Is(err, job.ErrNotFound),
+		errors.Is(err, domain.ErrItemNotFound):
+		resp.Code = CodeNotFound
+		return http.StatusNotFound, resp
+
+	case errors.Is(err, service.ErrDuplicateProject), errors.Is(err, job.ErrFinished), errors.Is(err, dao.ErrJobAlreadyApplied),
+		errors.Is(err, service.ErrConcurrentUpdate):
+		resp.Code = CodeConflict
+		return http.StatusConflict, resp
+
+	case errors.Is(err, dao.ErrVersionConflict):
+		resp.Code = CodePrecondition
+		return http.StatusPreconditionFailed, resp
+
+	case errors.Is(err, ErrIfMatchRequired):
+		resp.Code = CodeIfMatchRequired
+		return http.StatusPreconditionRequired, resp
+
+	case errors.As(err, &upErr) && (upErr.RateLimited || upErr.Unavailable()):
+		if upErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(upErr.RetryAfter.Seconds()))))
+		}
+		if upErr.RateLimited {
+			resp.Code = CodeRateLimited
+			return http.StatusTooManyRequests, resp
+		}
+		resp.Code = upstreamCode(err, CodeUnavailable)
+		return http.StatusServiceUnavailable, resp
+
+	case errors.Is(err, context.DeadlineExceeded) || errors.Is(c.Request.Context().Err(), context.DeadlineExceeded):
+		resp.Code, resp.Error = CodeTimeout, "请求处理超时，请稍后重试"
+		return http.StatusGatewayTimeout, resp
+
+	case errors.Is(err, agent.ErrModel), errors.Is(err, agent.ErrGitHub):
+		resp.Code = upstreamCode(err, CodeInternal)
+		return http.StatusBadGateway, resp
+	}
+
+	log.Printf("⚠️ 请求 %s %s 失败 [%s]: %v\n", c.Request.Method, c.Request.URL.Path, resp.RequestID, err)
+	resp.Code, resp.Error = CodeInternal, "服务器内部错误"
+	return http.StatusInternalServerError, resp
+}
+
+// upstreamCode 根据出错的上游返回错误码
+func upstreamCode(err error, fallback string) string {
+	switch {
+	case errors.Is(err, agent.ErrModel):
+		return CodeAIError
+	case errors.Is(err, agent.ErrGitHub):
+		return CodeGitHubError
+	}
+	return fallback
+}
@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/agent"
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/job"
+	"ResumeBuilder/internal/resilience"
+	"ResumeBuilder/internal/service"
+	"ResumeBuilder/internal/validation"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newErrorRouter 创建只挂载 RequestID 和 ErrorHandler 的路由，处理器记录 err 后返回
+func newErrorRouter(err error) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(RequestID(), ErrorHandler())
+	r.GET("/fail", func(c *gin.Context) { c.Error(err) })
+	r.GET("/ok", func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{}) })
+	return r
+}
+
+func TestErrorHandler(t *testing.T) {
+	required := &domain.ValidationError{Fields: []domain.FieldError{
+		{Field: "basic_info.name", Rule: validation.RuleRequired},
+	}}
+	rateLimited := &resilience.Error{Upstream: "github", StatusCode: http.StatusTooManyRequests, RateLimited: true,
+		RetryAfter: 1500 * time.Millisecond, Err: errors.New("API rate limit exceeded")}
+	circuitOpen := &resilience.Error{Upstream: "ark", RetryAfter: 30 * time.Second, Err: resilience.ErrCircuitOpen}
+	badGateway := &resilience.Error{Upstream: "ark", StatusCode: http.StatusBadGateway, Err: errors.New("bad gateway")}
+
+	tests := []struct {
+		name       string
+		err        error
+		lang       string // Accept-Language
+		wantStatus int
+		wantCode   string
+		wantError  string // 为空表示与 err.Error() 相同
+		retryAfter string // 期望的 Retry-After 响应头
+	}{
+		{"简历不存在", dao.ErrNotFound, "", http.StatusNotFound, CodeNotFound, "", ""},
+		{"修订不存在", fmt.Errorf("恢复修订: %w", dao.ErrRevisionNotFound), "", http.StatusNotFound, CodeNotFound, "", ""},
+		{"任务不存在", job.ErrNotFound, "", http.StatusNotFound, CodeNotFound, "", ""},
+		{"条目不存在", domain.ErrItemNotFound, "", http.StatusNotFound, CodeNotFound, "", ""},
+		{"校验失败", required, "", http.StatusBadRequest, CodeInvalidArgument, "basic_info.name: 不能为空", ""},
+		{"英文校验提示", required, "en-US,en;q=0.9", http.StatusBadRequest, CodeInvalidArgument, "basic_info.name: is required", ""},
+		{"项目重复", service.ErrDuplicateProject, "", http.StatusConflict, CodeConflict, "", ""},
+		{"任务已结束", job.ErrFinished, "", http.StatusConflict, CodeConflict, "", ""},
+		{"任务结果已写入", dao.ErrJobAlreadyApplied, "", http.StatusConflict, CodeConflict, "", ""},
+		{"频繁并发修改", service.ErrConcurrentUpdate, "", http.StatusConflict, CodeConflict, "", ""},
+		{"版本不一致", dao.ErrVersionConflict, "", http.StatusPreconditionFailed, CodePrecondition, "", ""},
+		{"缺少 If-Match", ErrIfMatchRequired, "", http.StatusPreconditionRequired, CodeIfMatchRequired, "", ""},
+		{"上游限流", fmt.Errorf("%w: %w", agent.ErrGitHub, rateLimited), "", http.StatusTooManyRequests, CodeRateLimited, "", "2"},
+		{"AI服务熔断", fmt.Errorf("%w: %w", agent.ErrModel, circuitOpen), "", http.StatusServiceUnavailable, CodeAIError, "", "30"},
+		{"其他上游熔断", circuitOpen, "", http.StatusServiceUnavailable, CodeUnavailable, "ark 服务暂时不可用，请稍后重试", "30"},
+		{"GitHub 不可用", fmt.Errorf("%w: %w", agent.ErrGitHub, badGateway), "", http.StatusServiceUnavailable, CodeGitHubError, "", ""},
+		{"模型输出无法使用", fmt.Errorf("%w: %w", agent.ErrModel, errors.New("输出中未找到 JSON 对象")), "", http.StatusBadGateway, CodeAIError, "", ""},
+		{"超时", fmt.Errorf("生成简历: %w", context.DeadlineExceeded), "", http.StatusGatewayTimeout, CodeTimeout, "请求处理超时，请稍后重试", ""},
+		{"未识别的错误不暴露内部信息", errors.New("dial tcp 10.0.0.1:3306: connection refused"), "", http.StatusInternalServerError, CodeInternal, "服务器内部错误", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+			req.Header.Set(RequestIDHeader, "req-1")
+			if tt.lang != "" {
+				req.Header.Set("Accept-Language", tt.lang)
+			}
+			w := httptest.NewRecorder()
+			newErrorRouter(tt.err).ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("状态码 = %d，期望 %d", w.Code, tt.wantStatus)
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应 %s: %v", w.Body, err)
+			}
+			wantError := tt.wantError
+			if wantError == "" {
+				wantError = tt.err.Error()
+			}
+			if resp.Code != tt.wantCode || resp.Error != wantError || resp.RequestID != "req-1" {
+				t.Errorf("响应 = %+v，期望 code=%s error=%q request_id=req-1", resp, tt.wantCode, wantError)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q，期望 %q", got, tt.retryAfter)
+			}
+			if got := w.Header().Get(RequestIDHeader); got != "req-1" {
+				t.Errorf("%s = %q，期望 req-1", RequestIDHeader, got)
+			}
+		})
+	}
+}
+
+func TestErrorHandlerDetails(t *testing.T) {
+	err := &domain.ValidationError{Fields: []domain.FieldError{
+		{Field: "basic_info.email", Rule: validation.RuleEmail},
+		{Field: "skills", Message: "技能不能重复"},
+	}}
+	w := httptest.NewRecorder()
+	newErrorRouter(err).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应 %s: %v", w.Body, err)
+	}
+	want := []domain.FieldError{
+		{Field: "basic_info.email", Rule: validation.RuleEmail, Message: "邮箱格式不正确"},
+		{Field: "skills", Message: "技能不能重复"},
+	}
+	if !reflect.DeepEqual(resp.Details, want) {
+		t.Errorf("details = %+v，期望 %+v", resp.Details, want)
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		path     string
+		header   string
+		generate bool // 是否应由服务端生成新的请求ID
+	}{
+		{"沿用客户端的请求ID", "/ok", "abc-123", false},
+		{"错误响应沿用客户端的请求ID", "/fail", "abc-123", false},
+		{"未提供时生成", "/fail", "", true},
+		{"过长时重新生成", "/fail", strings.Repeat("x", 65), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			newErrorRouter(dao.ErrNotFound).ServeHTTP(w, req)
+
+			got := w.Header().Get(RequestIDHeader)
+			if tt.generate {
+				if got == "" || got == tt.header {
+					t.Fatalf("%s = %q，期望新生成的请求ID", RequestIDHeader, got)
+				}
+			} else if got != tt.header {
+				t.Fatalf("%s = %q，期望 %q", RequestIDHeader, got, tt.header)
+			}
+			if tt.path != "/fail" {
+				return
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.RequestID != got {
+				t.Errorf("响应中的 request_id = %q, %v，期望与响应头相同 %q", resp.RequestID, err, got)
+			}
+		})
+	}
+}
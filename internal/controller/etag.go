@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/domain"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strconv"
+	"strings"
+)
+
+// ErrIfMatchRequired 配置要求修改简历时携带 If-Match，但请求中没有
+var ErrIfMatchRequired = errors.New("缺少 If-Match 请求头，请先读取简历获取 ETag")
+
+// setETag 将简历版本作为 ETag 返回，客户端修改时放入 If-Match
+func setETag(c *gin.Context, r *domain.Resume) {
+	if r != nil && r.Version > 0 {
+		c.Header("ETag", `"`+strconv.FormatInt(r.Version, 10)+`"`)
+	}
+}
+
+// ifMatch 解析 If-Match 请求头中的简历版本；没有该请求头或为 * 时返回 0，表示不检查版本
+func ifMatch(c *gin.Context) (int64, error) {
+	v := strings.TrimSpace(c.GetHeader("If-Match"))
+	if v == "" || v == "*" {
+		return 0, nil
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	version, err := strconv.ParseInt(v, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, domain.Invalid("If-Match", "If-Match 必须是读取简历时返回的 ETag")
+	}
+	return version, nil
+}
+
+// RequireIfMatch 要求请求携带 If-Match，required 为 false 时不做检查
+func RequireIfMatch(required bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if required && c.GetHeader("If-Match") == "" {
+			c.Error(ErrIfMatchRequired)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
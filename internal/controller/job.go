@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/job"
+	"ResumeBuilder/internal/service"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// JobController 处理异步任务的提交、查询和取消
+type JobController struct {
+	service service.JobService
+}
+
+// NewJobController 创建一个新的 JobController
+func NewJobController(service service.JobService) *JobController {
+	return &JobController{
+		service: service,
+	}
+}
+
+// SubmitGenerateJobHandler 提交AI生成简历任务，立即返回任务ID
+func (r *JobController) SubmitGenerateJobHandler(c *gin.Context) {
+	userID := c.Param("userID")
+	var req struct {
+		Raw  string `json:"raw"`
+		Mode string `json:"mode"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	j, err := r.service.SubmitGenerate(c.Request.Context(), userID, req.Raw, domain.MergeMode(req.Mode))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"job_id": j.ID, "status": j.Status})
+}
+
+// SubmitGitHubJobHandler 提交GitHub项目分析任务，完成后项目加入用户默认简历
+func (r *JobController) SubmitGitHubJobHandler(c *gin.Context) {
+	userID := c.Param("userID")
+	var req struct {
+		RepoURL string `json:"repo_url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	j, err := r.service.SubmitGitHub(c.Request.Context(), userID, req.RepoURL)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"job_id": j.ID, "status": j.Status})
+}
+
+// GetJobHandler 查询任务状态，任务成功后 result 中为结果
+func (r *JobController) GetJobHandler(c *gin.Context) {
+	j, err := r.service.GetJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, j)
+}
+
+// CancelJobHandler 取消任务；执行中的任务会尽快中断，可继续轮询确认取消结果
+func (r *JobController) CancelJobHandler(c *gin.Context) {
+	j, err := r.service.CancelJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	if j.Status == job.StatusCanceled {
+		c.JSON(http.StatusOK, j)
+		return
+	}
+	c.JSON(http.StatusAccepted, j)
+}
@@ -3,8 +3,8 @@ package controller
 import (
 	"ResumeBuilder/internal/domain"
 	"ResumeBuilder/internal/service"
-	"context"
 	"github.com/gin-gonic/gin"
+	"strconv"
 
 	"net/http"
 )
@@ -21,87 +21,117 @@ func NewResumeController(service service.ResumeService) *ResumeController {
 	}
 }
 
-// GetResumeHandler 获取简历
+// GetResumeHandler 获取简历，响应头 ETag 为简历当前版本
 func (r *ResumeController) GetResumeHandler(c *gin.Context) {
 	userID := c.Param("userID")
 
 	// 验证userID是否为空
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID不能为空"})
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
 		return
 	}
 
 	// 调用服务层获取简历
-	resume, err := r.service.GetResume(context.Background(), userID)
+	resume, err := r.service.GetResume(c.Request.Context(), userID, "")
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
+	setETag(c, resume)
 	c.JSON(http.StatusOK, resume)
 }
 
-// SaveResumeHandler 保存简历
+// SaveResumeHandler 保存简历，携带 If-Match 时仅当简历仍是该版本才保存，否则返回 412
 func (r *ResumeController) SaveResumeHandler(c *gin.Context) {
 	var resume domain.Resume
 	if err := c.ShouldBindJSON(&resume); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		c.Error(domain.Invalid("", "Invalid input"))
 		return
 	}
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	// 版本只以 If-Match 为准，忽略请求体中的 version
+	resume.Version = version
 
 	// 调用服务层保存简历
-	err := r.service.SaveResume(context.Background(), &resume)
+	err = r.service.SaveResume(c.Request.Context(), &resume)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Resume saved successfully"})
+	setETag(c, &resume)
+	c.JSON(http.StatusOK, gin.H{"message": "Resume saved successfully", "version": resume.Version})
 }
 
 // GenerateResumeHandler 根据原始文本生成简历
+// mode 为 replace（默认）时直接返回生成的简历；为 merge / preview 时返回合并后的简历和合并报告
 func (r *ResumeController) GenerateResumeHandler(c *gin.Context) {
 	var request struct {
-		Raw string `json:"raw" binding:"required"`
+		Raw  string `json:"raw" binding:"required"`
+		Mode string `json:"mode"`
 	}
 
 	userID := c.Param("userID")
 
 	// 验证userID是否为空
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID不能为空"})
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
 		return
 	}
 
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	mode, err := domain.ParseMergeMode(request.Mode)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
 	// 调用服务层生成简历
-	resume, err := r.service.GenerateResume(context.Background(), request.Raw, userID)
+	resume, report, err := r.service.GenerateResume(c.Request.Context(), request.Raw, userID, mode)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
-	c.JSON(http.StatusOK, resume)
+	c.JSON(http.StatusOK, generateResponse(mode, resume, report))
+}
+
+// generateResponse 构造生成接口的响应：replace 模式直接返回简历，其余模式附带合并报告
+func generateResponse(mode domain.MergeMode, resume *domain.Resume, report *domain.MergeReport) any {
+	if mode == domain.MergeReplace {
+		return resume
+	}
+	return gin.H{
+		"mode":   mode,
+		"saved":  mode != domain.MergePreview,
+		"resume": resume,
+		"report": report,
+	}
 }
 
-// DeleteResumeHandler 删除简历
+// DeleteResumeHandler 将默认简历移入回收站
 func (r *ResumeController) DeleteResumeHandler(c *gin.Context) {
 	userID := c.Param("userID")
 
 	// 验证userID是否为空
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID不能为空"})
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
 		return
 	}
 
 	// 调用服务层删除简历
-	err := r.service.DeleteResume(context.Background(), userID)
+	err := r.service.DeleteResume(c.Request.Context(), userID, "")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
@@ -114,23 +144,155 @@ func (r *ResumeController) AddGitHubProjectHandler(c *gin.Context) {
 		RepoURL string `json:"repo_url" binding:"required,url"`
 	}
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "无效的仓库地址"})
+		c.Error(domain.Invalid("repo_url", "无效的仓库地址"))
+		return
+	}
+
+	userID := c.Param("userID")
+
+	// 验证userID是否为空
+	if userID == "" {
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
+		return
+	}
+
+	resume, err := r.service.AnalyzeAndAddGitHubProject(c.Request.Context(), userID, req.RepoURL)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// PreviewGitHubProjectHandler 分析GitHub项目并返回结果，不写入简历
+func (r *ResumeController) PreviewGitHubProjectHandler(c *gin.Context) {
+	var req struct {
+		RepoURL string `json:"repo_url" binding:"required,url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("repo_url", "无效的仓库地址"))
+		return
+	}
+
+	userID := c.Param("userID")
+
+	// 验证userID是否为空
+	if userID == "" {
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
+		return
+	}
+
+	project, err := r.service.PreviewGitHubProject(c.Request.Context(), userID, req.RepoURL)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// ConfirmGitHubProjectHandler 将预览（可能经用户修改）后的项目添加到用户简历
+func (r *ResumeController) ConfirmGitHubProjectHandler(c *gin.Context) {
+	var req struct {
+		Project *domain.Project `json:"project" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	userID := c.Param("userID")
+
+	// 验证userID是否为空
+	if userID == "" {
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
+		return
+	}
+
+	resume, err := r.service.AddProject(c.Request.Context(), userID, req.Project)
+	if err != nil {
+		c.Error(err)
 		return
 	}
 
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// ListRevisionsHandler 列出简历的修订历史，未指定 resumeID 时为默认简历
+func (r *ResumeController) ListRevisionsHandler(c *gin.Context) {
 	userID := c.Param("userID")
+	resumeID := c.Param("resumeID")
 
 	// 验证userID是否为空
 	if userID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "用户ID不能为空"})
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
+		return
+	}
+
+	revisions, err := r.service.ListRevisions(c.Request.Context(), userID, resumeID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"revisions": revisions})
+}
+
+// GetRevisionHandler 获取指定修订的完整内容
+func (r *ResumeController) GetRevisionHandler(c *gin.Context) {
+	userID := c.Param("userID")
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.Error(domain.Invalid("revision", "无效的修订号"))
+		return
+	}
+
+	rev, err := r.service.GetRevision(c.Request.Context(), userID, revision)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// RestoreRevisionHandler 将简历恢复到指定修订
+func (r *ResumeController) RestoreRevisionHandler(c *gin.Context) {
+	userID := c.Param("userID")
+	revision, err := strconv.Atoi(c.Param("revision"))
+	if err != nil || revision <= 0 {
+		c.Error(domain.Invalid("revision", "无效的修订号"))
 		return
 	}
 
-	resume, err := r.service.AnalyzeAndAddGitHubProject(context.Background(), userID, req.RepoURL)
+	resume, err := r.service.RestoreRevision(c.Request.Context(), userID, revision)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.Error(err)
 		return
 	}
 
+	setETag(c, resume)
 	c.JSON(http.StatusOK, resume)
 }
+
+// DiffRevisionsHandler 比较两个修订，查询参数 from、to 为修订号
+func (r *ResumeController) DiffRevisionsHandler(c *gin.Context) {
+	userID := c.Param("userID")
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil || from <= 0 || to <= 0 {
+		c.Error(domain.Invalid("", "from 和 to 必须是有效的修订号"))
+		return
+	}
+
+	diff, err := r.service.DiffRevisions(c.Request.Context(), userID, from, to)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "diff": diff})
+}
@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/domain"
+	"github.com/gin-gonic/gin"
+
+	"net/http"
+)
+
+// ListResumesHandler 列出用户的全部简历
+func (r *ResumeController) ListResumesHandler(c *gin.Context) {
+	userID := c.Param("userID")
+
+	resumes, err := r.service.ListResumes(c.Request.Context(), userID)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resumes": resumes})
+}
+
+// CreateResumeHandler 新建一份简历，可同时提交简历内容
+func (r *ResumeController) CreateResumeHandler(c *gin.Context) {
+	var req struct {
+		Name   string         `json:"name"`
+		Resume *domain.Resume `json:"resume"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	resume, err := r.service.CreateResume(c.Request.Context(), c.Param("userID"), req.Name, req.Resume)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusCreated, resume)
+}
+
+// GetResumeByIDHandler 获取指定简历
+func (r *ResumeController) GetResumeByIDHandler(c *gin.Context) {
+	resume, err := r.service.GetResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// UpdateResumeByIDHandler 保存指定简历的内容，携带 If-Match 时仅当简历仍是该版本才保存
+func (r *ResumeController) UpdateResumeByIDHandler(c *gin.Context) {
+	var resume domain.Resume
+	if err := c.ShouldBindJSON(&resume); err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	resume.UserID = c.Param("userID")
+	resume.ID = c.Param("resumeID")
+	resume.Version = version
+
+	if err := r.service.SaveResume(c.Request.Context(), &resume); err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, &resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// DeleteResumeByIDHandler 将指定简历移入回收站
+func (r *ResumeController) DeleteResumeByIDHandler(c *gin.Context) {
+	err := r.service.DeleteResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resume deleted successfully"})
+}
+
+// CloneResumeHandler 复制指定简历，可指定新名称
+func (r *ResumeController) CloneResumeHandler(c *gin.Context) {
+	var req struct {
+		Name string `json:"name"`
+	}
+	// 请求体可以为空
+	_ = c.ShouldBindJSON(&req)
+
+	resume, err := r.service.CloneResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), req.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusCreated, resume)
+}
+
+// RenameResumeHandler 重命名指定简历
+func (r *ResumeController) RenameResumeHandler(c *gin.Context) {
+	var req struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("name", "简历名称不能为空"))
+		return
+	}
+
+	err := r.service.RenameResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), req.Name)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Resume renamed successfully"})
+}
+
+// SetDefaultResumeHandler 将指定简历设为默认简历
+func (r *ResumeController) SetDefaultResumeHandler(c *gin.Context) {
+	err := r.service.SetDefaultResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Default resume updated successfully"})
+}
+
+// ListTrashHandler 列出回收站中的简历，最近删除的在前
+func (r *ResumeController) ListTrashHandler(c *gin.Context) {
+	resumes, err := r.service.ListTrash(c.Request.Context(), c.Param("userID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"resumes": resumes})
+}
+
+// RestoreResumeHandler 从回收站恢复简历，返回恢复后的简历
+func (r *ResumeController) RestoreResumeHandler(c *gin.Context) {
+	resume, err := r.service.RestoreResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
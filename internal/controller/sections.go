@@ -0,0 +1,134 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/domain"
+	"github.com/gin-gonic/gin"
+	"strconv"
+
+	"net/http"
+)
+
+// 以下接口同时注册在 /resume/:userID（默认简历）和 /users/:userID/resumes/:resumeID 下，
+// 默认简历的路由没有 resumeID 参数，c.Param 返回空即表示默认简历。
+// 请求携带 If-Match 时仅当简历仍是该版本才修改，否则返回 412；响应头 ETag 为修改后的版本
+
+// PatchResumeHandler 按 RFC 7396（JSON Merge Patch）修改简历内容，返回修改后的简历
+func (r *ResumeController) PatchResumeHandler(c *gin.Context) {
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	resume, err := r.service.PatchResume(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), version, patch)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// AddItemHandler 向章节添加条目，可用 ?position= 指定插入位置（从 0 开始），默认追加到末尾
+func (r *ResumeController) AddItemHandler(c *gin.Context) {
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	position := -1
+	if s := c.Query("position"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			c.Error(domain.Invalid("position", "position 必须是非负整数"))
+			return
+		}
+		position = n
+	}
+	item, err := c.GetRawData()
+	if err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	resume, id, err := r.service.AddItem(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), version, c.Param("section"), item, position)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusCreated, gin.H{"id": id, "resume": resume})
+}
+
+// UpdateItemHandler 按 RFC 7396 修改单个条目；技能条目的请求体为新的技能文本（JSON 字符串）
+func (r *ResumeController) UpdateItemHandler(c *gin.Context) {
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	patch, err := c.GetRawData()
+	if err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	resume, err := r.service.UpdateItem(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), version, c.Param("section"), c.Param("itemID"), patch)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// DeleteItemHandler 删除单个条目
+func (r *ResumeController) DeleteItemHandler(c *gin.Context) {
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	resume, err := r.service.DeleteItem(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), version, c.Param("section"), c.Param("itemID"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
+
+// ReorderItemsHandler 按请求中的条目ID顺序重新排列章节，ids 必须包含该章节的全部条目
+func (r *ResumeController) ReorderItemsHandler(c *gin.Context) {
+	version, err := ifMatch(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	var req struct {
+		IDs []string `json:"ids" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("ids", "ids 不能为空"))
+		return
+	}
+
+	resume, err := r.service.ReorderItems(c.Request.Context(), c.Param("userID"), c.Param("resumeID"), version, c.Param("section"), req.IDs)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	setETag(c, resume)
+	c.JSON(http.StatusOK, resume)
+}
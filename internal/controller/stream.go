@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"ResumeBuilder/internal/agent"
+	"ResumeBuilder/internal/domain"
+	"github.com/gin-gonic/gin"
+
+	"net/http"
+)
+
+// SSE 事件名称
+const (
+	eventStage  = "stage"  // 阶段变化：{"stage": "calling_model"}
+	eventToken  = "token"  // 模型输出片段：{"content": "...", "reasoning": "..."}
+	eventResult = "result" // 最终结果，与对应的非流式接口响应相同
+	eventError  = "error"  // 出错：与普通接口相同的错误响应（见 ErrorResponse），之后连接关闭
+)
+
+// startSSE 设置 SSE 响应头，返回写入事件并立即刷新的函数
+func startSSE(c *gin.Context) func(event string, data any) {
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // 避免 Nginx 缓冲事件
+	c.Status(http.StatusOK)
+
+	return func(event string, data any) {
+		c.SSEvent(event, data)
+		c.Writer.Flush()
+	}
+}
+
+// sseProgress 将生成进度转发为 SSE 事件
+func sseProgress(send func(event string, data any)) agent.Progress {
+	return agent.Progress{
+		OnStage: func(stage agent.Stage) {
+			send(eventStage, gin.H{"stage": stage})
+		},
+		OnDelta: func(delta agent.StreamDelta) {
+			send(eventToken, delta)
+		},
+	}
+}
+
+// GenerateResumeStreamHandler 流式生成简历，通过 SSE 推送阶段变化、模型输出和最终结果
+func (r *ResumeController) GenerateResumeStreamHandler(c *gin.Context) {
+	var request struct {
+		Raw  string `json:"raw" binding:"required"`
+		Mode string `json:"mode"`
+	}
+
+	userID := c.Param("userID")
+
+	// 验证userID是否为空
+	if userID == "" {
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
+		return
+	}
+
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.Error(domain.Invalid("", "Invalid input"))
+		return
+	}
+
+	mode, err := domain.ParseMergeMode(request.Mode)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	send := startSSE(c)
+	resume, report, err := r.service.GenerateResumeStream(c.Request.Context(), request.Raw, userID, mode, sseProgress(send))
+	if err != nil {
+		_, resp := errorResponse(c, err)
+		send(eventError, resp)
+		return
+	}
+
+	send(eventResult, generateResponse(mode, resume, report))
+}
+
+// AddGitHubProjectStreamHandler 流式分析GitHub项目并添加到用户简历
+func (r *ResumeController) AddGitHubProjectStreamHandler(c *gin.Context) {
+	var req struct {
+		RepoURL string `json:"repo_url" binding:"required,url"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(domain.Invalid("repo_url", "无效的仓库地址"))
+		return
+	}
+
+	userID := c.Param("userID")
+
+	// 验证userID是否为空
+	if userID == "" {
+		c.Error(domain.Invalid("user_id", "用户ID不能为空"))
+		return
+	}
+
+	send := startSSE(c)
+	resume, err := r.service.AnalyzeAndAddGitHubProjectStream(c.Request.Context(), userID, req.RepoURL, sseProgress(send))
+	if err != nil {
+		_, resp := errorResponse(c, err)
+		send(eventError, resp)
+		return
+	}
+
+	send(eventResult, resume)
+}
@@ -0,0 +1,46 @@
+package dao_test
+
+import (
+	"ResumeBuilder/internal/cache"
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/dao/daotest"
+	"path/filepath"
+	"testing"
+)
+
+func TestMemoryDAO(t *testing.T) {
+	daotest.Run(t, func(t *testing.T) dao.ResumeDAO { return dao.NewMemoryDAO() })
+}
+
+func TestSQLiteDAO(t *testing.T) {
+	caches := []struct {
+		name string
+		new  func() cache.Cache
+	}{
+		{"NoCache", func() cache.Cache { return cache.Noop{} }},
+		{"MemoryCache", func() cache.Cache { return cache.NewMemory(1000) }},
+	}
+	for _, c := range caches {
+		t.Run(c.name, func(t *testing.T) {
+			daotest.Run(t, func(t *testing.T) dao.ResumeDAO {
+				return openSQLite(t, c.new())
+			})
+		})
+	}
+}
+
+// openSQLite 在临时文件中创建执行过全部迁移的 SQLite 存储
+func openSQLite(t *testing.T, store cache.Cache) dao.ResumeDAO {
+	t.Helper()
+	dbCfg := config.DBConfig{
+		Driver:      "sqlite",
+		DSN:         filepath.Join(t.TempDir(), "resume.db"),
+		AutoMigrate: true,
+	}
+	d, err := dao.NewResumeDAO(dbCfg, store, config.Default().Cache)
+	if err != nil {
+		t.Fatalf("NewResumeDAO: %v", err)
+	}
+	return d
+}
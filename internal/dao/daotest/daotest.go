@@ -0,0 +1,631 @@
+// Package daotest 提供 dao.ResumeDAO 的契约测试，每种存储实现都必须通过：
+//
+//	func TestMemoryDAO(t *testing.T) {
+//		daotest.Run(t, func(t *testing.T) dao.ResumeDAO { return dao.NewMemoryDAO() })
+//	}
+package daotest
+
+import (
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/domain"
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Run 对 open 创建的存储运行全部契约测试，每个子测试使用一个新的空存储
+func Run(t *testing.T, open func(t *testing.T) dao.ResumeDAO) {
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, d dao.ResumeDAO)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"List", testList},
+		{"Update", testUpdate},
+		{"ExtendedSections", testExtendedSections},
+		{"CustomSections", testCustomSections},
+		{"SkillIDs", testSkillIDs},
+		{"Modify", testModify},
+		{"RenameAndSetDefault", testRenameAndSetDefault},
+		{"Delete", testDelete},
+		{"Trash", testTrash},
+		{"Revisions", testRevisions},
+		{"RevisionsAfterPurge", testRevisionsAfterPurge},
+		{"ConcurrentRevisions", testConcurrentRevisions},
+		{"ConcurrentCreate", testConcurrentCreate},
+		{"JobIdempotency", testJobIdempotency},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := open(t)
+			t.Cleanup(func() { d.Close() })
+			tt.fn(t, d)
+		})
+	}
+}
+
+func newResume(userID, name string) *domain.Resume {
+	return &domain.Resume{
+		UserID:    userID,
+		BasicInfo: []domain.BasicInfo{{Name: name, Email: name + "@example.com"}},
+		Education: []domain.Education{{School: "清华大学", Major: "计算机科学", Degree: "本科"}},
+		Skills:    []string{"Go", "MySQL"},
+	}
+}
+
+// mustCreate 创建简历，失败时终止测试
+func mustCreate(t *testing.T, d dao.ResumeDAO, r *domain.Resume) *domain.Resume {
+	t.Helper()
+	if err := d.Create(context.Background(), r, domain.SourceManual); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	return r
+}
+
+// mustGet 读取简历，失败时终止测试
+func mustGet(t *testing.T, d dao.ResumeDAO, userID, resumeID string) *domain.Resume {
+	t.Helper()
+	r, err := d.Get(context.Background(), userID, resumeID)
+	if err != nil {
+		t.Fatalf("Get(%q, %q): %v", userID, resumeID, err)
+	}
+	return r
+}
+
+// wantErr 检查 err 是否为 want
+func wantErr(t *testing.T, op string, err, want error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Fatalf("%s: 错误为 %v，期望 %v", op, err, want)
+	}
+}
+
+func testCreateAndGet(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+	if r.ID == "" || !r.IsDefault || r.Version != 1 || r.Name != domain.DefaultResumeName {
+		t.Fatalf("Create 后 ID=%q IsDefault=%v Version=%d Name=%q", r.ID, r.IsDefault, r.Version, r.Name)
+	}
+	if r.Education[0].ID == "" {
+		t.Fatal("Create 未生成条目ID")
+	}
+
+	for _, id := range []string{"", r.ID} {
+		got := mustGet(t, d, "u1", id)
+		if got.ID != r.ID || got.Version != 1 || got.BasicInfo[0].Name != "张三" || len(got.Skills) != 2 {
+			t.Fatalf("Get(%q) = %+v", id, got)
+		}
+		if got.Education[0].ID != r.Education[0].ID {
+			t.Fatalf("条目ID %q，期望 %q", got.Education[0].ID, r.Education[0].ID)
+		}
+	}
+
+	_, err := d.Get(ctx, "u2", r.ID)
+	wantErr(t, "读取其他用户的简历", err, dao.ErrNotFound)
+	_, err = d.Get(ctx, "u1", "missing")
+	wantErr(t, "读取不存在的简历", err, dao.ErrNotFound)
+	_, err = d.Get(ctx, "u2", "")
+	wantErr(t, "读取没有简历的用户的默认简历", err, dao.ErrNotFound)
+
+	// 不存在的结果可能被缓存，创建后必须能读到
+	mustCreate(t, d, newResume("u2", "李四"))
+	mustGet(t, d, "u2", "")
+}
+
+func testList(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	first := mustCreate(t, d, newResume("u1", "张三"))
+	second := newResume("u1", "张三")
+	second.Name = "英文简历"
+	mustCreate(t, d, second)
+	if second.IsDefault {
+		t.Fatal("第二份简历不应成为默认简历")
+	}
+
+	list, err := d.List(ctx, "u1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(list) != 2 || list[0].ID != first.ID || !list[0].IsDefault || list[1].Name != "英文简历" {
+		t.Fatalf("List = %+v", list)
+	}
+
+	list, err = d.List(ctx, "u2")
+	if err != nil || len(list) != 0 {
+		t.Fatalf("没有简历的用户 List = %+v, %v", list, err)
+	}
+}
+
+func testUpdate(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+	eduID := r.Education[0].ID
+
+	// 名称、默认标记等元数据以存储为准；缺少ID的同一条目沿用原ID
+	update := newResume("u1", "张三丰")
+	update.ID, update.Version, update.Name, update.IsDefault = r.ID, 1, "改名", false
+	if err := d.Update(ctx, update, domain.SourceManual); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if update.Version != 2 {
+		t.Fatalf("Update 后版本为 %d，期望 2", update.Version)
+	}
+	got := mustGet(t, d, "u1", r.ID)
+	if got.Version != 2 || got.BasicInfo[0].Name != "张三丰" || got.Name != domain.DefaultResumeName || !got.IsDefault {
+		t.Fatalf("Update 后 Get = %+v", got)
+	}
+	if got.Education[0].ID != eduID {
+		t.Fatalf("条目ID %q，期望沿用 %q", got.Education[0].ID, eduID)
+	}
+
+	stale := newResume("u1", "王五")
+	stale.ID, stale.Version = r.ID, 1
+	wantErr(t, "以旧版本 Update", d.Update(ctx, stale, domain.SourceManual), dao.ErrVersionConflict)
+	if got := mustGet(t, d, "u1", r.ID); got.Version != 2 || got.BasicInfo[0].Name != "张三丰" {
+		t.Fatalf("版本冲突后内容被修改: %+v", got)
+	}
+
+	// 版本为 0 时不检查版本，ID 为空时更新默认简历
+	unconditional := newResume("u1", "王五")
+	if err := d.Update(ctx, unconditional, domain.SourceManual); err != nil {
+		t.Fatalf("不带版本 Update: %v", err)
+	}
+	if unconditional.ID != r.ID || unconditional.Version != 3 {
+		t.Fatalf("不带版本 Update 后 ID=%q Version=%d", unconditional.ID, unconditional.Version)
+	}
+
+	missing := newResume("u1", "王五")
+	missing.ID = "missing"
+	wantErr(t, "Update 不存在的简历", d.Update(ctx, missing, domain.SourceManual), dao.ErrNotFound)
+	other := newResume("u2", "王五")
+	other.ID = r.ID
+	wantErr(t, "Update 其他用户的简历", d.Update(ctx, other, domain.SourceManual), dao.ErrNotFound)
+}
+
+func testExtendedSections(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := newResume("u1", "张三")
+	r.Summary = "五年后端开发经验"
+	r.Certifications = []domain.Certification{{Name: "PMP", Issuer: "PMI"}}
+	r.Awards = []domain.Award{{Title: "ACM 区域赛银奖"}}
+	r.Languages = []domain.Language{{Language: "英语", Proficiency: "CET-6"}}
+	r.Links = []domain.Link{{Label: "GitHub", URL: "https://github.com/zhangsan"}}
+	r.Publications = []domain.Publication{{Title: "分布式缓存一致性研究", Publisher: "软件学报"}}
+	mustCreate(t, d, r)
+
+	got := mustGet(t, d, "u1", r.ID)
+	if got.Summary != r.Summary || got.Certifications[0].Issuer != "PMI" || got.Awards[0].Title != r.Awards[0].Title ||
+		got.Languages[0].Proficiency != "CET-6" || got.Links[0].URL != r.Links[0].URL || got.Publications[0].Publisher != "软件学报" {
+		t.Fatalf("Get = %+v", got)
+	}
+	if got.Certifications[0].ID == "" || got.Certifications[0].ID != r.Certifications[0].ID {
+		t.Fatalf("证书条目ID %q，期望 %q", got.Certifications[0].ID, r.Certifications[0].ID)
+	}
+
+	// 不带ID写回同一条目时沿用原有ID
+	id := got.Links[0].ID
+	got.Links[0].ID = ""
+	got.Links[0].Label = "代码仓库"
+	if err := d.Update(ctx, got, domain.SourceManual); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if got := mustGet(t, d, "u1", r.ID); got.Links[0].ID != id || got.Links[0].Label != "代码仓库" {
+		t.Fatalf("链接 = %+v，期望ID %q", got.Links[0], id)
+	}
+}
+
+func testCustomSections(t *testing.T, d dao.ResumeDAO) {
+	r := newResume("u1", "张三")
+	r.CustomSections = []domain.CustomSection{
+		{Title: "开源贡献", Entries: []domain.CustomEntry{{Title: "gorm", Subtitle: "Contributor", Bullets: []string{"修复 SQLite 迁移问题"}}}},
+		{Title: "志愿经历", Entries: []domain.CustomEntry{{Title: "支教", Subtitle: "云南"}}},
+	}
+	mustCreate(t, d, r)
+
+	got := mustGet(t, d, "u1", r.ID)
+	if len(got.CustomSections) != 2 || got.CustomSections[0].Title != "开源贡献" || got.CustomSections[1].Entries[0].Subtitle != "云南" {
+		t.Fatalf("CustomSections = %+v", got.CustomSections)
+	}
+	section, entry := got.CustomSections[0].ID, got.CustomSections[0].Entries[0].ID
+	if section == "" || entry == "" {
+		t.Fatal("Create 未生成自定义章节或条目的ID")
+	}
+
+	// 通过条目接口修改自定义章节中的条目，章节和条目ID保持不变
+	modified, err := d.Modify(context.Background(), "u1", r.ID, domain.SourceManual, func(r *domain.Resume) error {
+		return r.PatchItem(domain.CustomSectionPrefix+section, entry, []byte(`{"url":"https://github.com/go-gorm/gorm"}`))
+	})
+	if err != nil {
+		t.Fatalf("Modify: %v", err)
+	}
+	got = mustGet(t, d, "u1", r.ID)
+	if e := got.CustomSections[0].Entries[0]; got.CustomSections[0].ID != section || e.ID != entry ||
+		e.URL != "https://github.com/go-gorm/gorm" || e.Title != "gorm" || got.Version != modified.Version {
+		t.Fatalf("修改后 = %+v", got.CustomSections[0])
+	}
+}
+
+func testSkillIDs(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+	got := mustGet(t, d, "u1", r.ID)
+	if len(got.SkillIDs) != 2 || got.SkillIDs[0] == "" || got.SkillIDs[0] == got.SkillIDs[1] {
+		t.Fatalf("SkillIDs = %v", got.SkillIDs)
+	}
+	mysql := got.SkillIDs[1]
+
+	// 删除前面的技能后，其余技能的ID不变
+	_, err := d.Modify(ctx, "u1", r.ID, domain.SourcePatch, func(r *domain.Resume) error {
+		return r.DeleteItem(domain.SectionSkills, r.SkillIDs[0])
+	})
+	if err != nil {
+		t.Fatalf("Modify: %v", err)
+	}
+	got = mustGet(t, d, "u1", r.ID)
+	if len(got.Skills) != 1 || got.Skills[0] != "MySQL" || got.SkillIDs[0] != mysql {
+		t.Fatalf("删除后 Skills = %v, SkillIDs = %v", got.Skills, got.SkillIDs)
+	}
+
+	// 整体替换技能时，按文本沿用已有技能的ID
+	_, err = d.Modify(ctx, "u1", r.ID, domain.SourcePatch, func(r *domain.Resume) error {
+		return r.ApplyMergePatch([]byte(`{"skills":["Redis","MySQL"]}`))
+	})
+	if err != nil {
+		t.Fatalf("Modify: %v", err)
+	}
+	got = mustGet(t, d, "u1", r.ID)
+	if len(got.SkillIDs) != 2 || got.SkillIDs[1] != mysql || got.SkillIDs[0] == "" || got.SkillIDs[0] == mysql {
+		t.Fatalf("替换后 Skills = %v, SkillIDs = %v", got.Skills, got.SkillIDs)
+	}
+}
+
+func testModify(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+
+	failure := errors.New("修改失败")
+	_, err := d.Modify(ctx, "u1", r.ID, domain.SourcePatch, func(r *domain.Resume) error {
+		r.Skills = nil
+		return failure
+	})
+	wantErr(t, "fn 返回错误时 Modify", err, failure)
+	if got := mustGet(t, d, "u1", r.ID); got.Version != 1 || len(got.Skills) != 2 {
+		t.Fatalf("fn 返回错误后内容被修改: %+v", got)
+	}
+
+	modified, err := d.Modify(ctx, "u1", "", domain.SourcePatch, func(r *domain.Resume) error {
+		if r.Version != 1 {
+			return dao.ErrVersionConflict
+		}
+		r.Skills = append(r.Skills, "Redis")
+		r.Name = "改名"
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Modify: %v", err)
+	}
+	if modified.Version != 2 || len(modified.Skills) != 3 || modified.Name != domain.DefaultResumeName {
+		t.Fatalf("Modify 返回 %+v", modified)
+	}
+	if got := mustGet(t, d, "u1", r.ID); got.Version != 2 || len(got.Skills) != 3 {
+		t.Fatalf("Modify 后 Get = %+v", got)
+	}
+
+	_, err = d.Modify(ctx, "u2", r.ID, domain.SourcePatch, func(*domain.Resume) error { return nil })
+	wantErr(t, "Modify 其他用户的简历", err, dao.ErrNotFound)
+}
+
+func testRenameAndSetDefault(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	first := mustCreate(t, d, newResume("u1", "张三"))
+	second := mustCreate(t, d, newResume("u1", "张三"))
+
+	if err := d.Rename(ctx, "u1", second.ID, "英文简历"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	if got := mustGet(t, d, "u1", second.ID); got.Name != "英文简历" || got.Version != 2 {
+		t.Fatalf("Rename 后 Name=%q Version=%d", got.Name, got.Version)
+	}
+	wantErr(t, "Rename 其他用户的简历", d.Rename(ctx, "u2", second.ID, "x"), dao.ErrNotFound)
+
+	if err := d.SetDefault(ctx, "u1", second.ID); err != nil {
+		t.Fatalf("SetDefault: %v", err)
+	}
+	if got := mustGet(t, d, "u1", ""); got.ID != second.ID || !got.IsDefault || got.Version != 3 {
+		t.Fatalf("SetDefault 后默认简历 ID=%q IsDefault=%v Version=%d", got.ID, got.IsDefault, got.Version)
+	}
+	if got := mustGet(t, d, "u1", first.ID); got.IsDefault || got.Version != 2 {
+		t.Fatalf("原默认简历 IsDefault=%v Version=%d", got.IsDefault, got.Version)
+	}
+	list, err := d.List(ctx, "u1")
+	if err != nil || len(list) != 2 || list[0].ID != second.ID {
+		t.Fatalf("SetDefault 后 List = %+v, %v", list, err)
+	}
+
+	// 已是默认简历时不做修改
+	if err := d.SetDefault(ctx, "u1", second.ID); err != nil {
+		t.Fatalf("重复 SetDefault: %v", err)
+	}
+	if got := mustGet(t, d, "u1", second.ID); got.Version != 3 {
+		t.Fatalf("重复 SetDefault 后版本为 %d，期望 3", got.Version)
+	}
+	wantErr(t, "SetDefault 不存在的简历", d.SetDefault(ctx, "u1", "missing"), dao.ErrNotFound)
+}
+
+func testDelete(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	first := mustCreate(t, d, newResume("u1", "张三"))
+	second := mustCreate(t, d, newResume("u1", "张三"))
+	third := mustCreate(t, d, newResume("u1", "张三"))
+
+	// 删除默认简历时由最近更新的另一份接替
+	time.Sleep(10 * time.Millisecond)
+	second.Version = 0
+	if err := d.Update(ctx, second, domain.SourceManual); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := d.Delete(ctx, "u1", ""); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	_, err := d.Get(ctx, "u1", first.ID)
+	wantErr(t, "读取已删除的简历", err, dao.ErrNotFound)
+	if got := mustGet(t, d, "u1", ""); got.ID != second.ID {
+		t.Fatalf("接替默认的简历为 %q，期望 %q", got.ID, second.ID)
+	}
+
+	wantErr(t, "重复 Delete", d.Delete(ctx, "u1", first.ID), dao.ErrNotFound)
+	wantErr(t, "Delete 其他用户的简历", d.Delete(ctx, "u2", third.ID), dao.ErrNotFound)
+
+	for _, id := range []string{third.ID, second.ID} {
+		if err := d.Delete(ctx, "u1", id); err != nil {
+			t.Fatalf("Delete(%q): %v", id, err)
+		}
+	}
+	_, err = d.Get(ctx, "u1", "")
+	wantErr(t, "删除全部简历后读取默认简历", err, dao.ErrNotFound)
+}
+
+func testTrash(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	first := mustCreate(t, d, newResume("u1", "张三"))
+	second := mustCreate(t, d, newResume("u1", "张三"))
+
+	for _, id := range []string{first.ID, second.ID} {
+		if err := d.Delete(ctx, "u1", id); err != nil {
+			t.Fatalf("Delete(%q): %v", id, err)
+		}
+	}
+	trash, err := d.ListTrash(ctx, "u1")
+	if err != nil {
+		t.Fatalf("ListTrash: %v", err)
+	}
+	if len(trash) != 2 || trash[0].ID != second.ID || trash[0].DeletedAt == nil || trash[0].IsDefault {
+		t.Fatalf("ListTrash = %+v", trash)
+	}
+	if list, _ := d.List(ctx, "u1"); len(list) != 0 {
+		t.Fatalf("回收站中的简历出现在 List 中: %+v", list)
+	}
+	if trash, _ := d.ListTrash(ctx, "u2"); len(trash) != 0 {
+		t.Fatalf("其他用户的回收站 = %+v", trash)
+	}
+
+	// 没有其他简历时恢复的简历成为默认简历，否则不是；
+	// second 的版本：创建 1，接替默认 2，移入回收站 3，恢复 4
+	wantErr(t, "Restore 其他用户的简历", d.Restore(ctx, "u2", second.ID), dao.ErrNotFound)
+	if err := d.Restore(ctx, "u1", second.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := mustGet(t, d, "u1", ""); got.ID != second.ID || got.Version != 4 || got.BasicInfo[0].Name != "张三" {
+		t.Fatalf("Restore 后默认简历 ID=%q Version=%d", got.ID, got.Version)
+	}
+	if err := d.Restore(ctx, "u1", first.ID); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := mustGet(t, d, "u1", first.ID); got.IsDefault {
+		t.Fatal("已有默认简历时恢复的简历不应成为默认简历")
+	}
+	wantErr(t, "Restore 不在回收站中的简历", d.Restore(ctx, "u1", first.ID), dao.ErrNotFound)
+
+	// 永久删除只作用于回收站中早于期限的简历，修订随之删除
+	if err := d.Delete(ctx, "u1", first.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n, err := d.Purge(ctx, time.Now().Add(-time.Hour)); err != nil || n != 0 {
+		t.Fatalf("Purge 未到期的简历 = %d, %v", n, err)
+	}
+	if n, err := d.Purge(ctx, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v", n, err)
+	}
+	if trash, _ := d.ListTrash(ctx, "u1"); len(trash) != 0 {
+		t.Fatalf("Purge 后回收站 = %+v", trash)
+	}
+	wantErr(t, "Restore 已永久删除的简历", d.Restore(ctx, "u1", first.ID), dao.ErrNotFound)
+	_, err = d.GetRevision(ctx, "u1", 1)
+	wantErr(t, "读取已永久删除的简历的修订", err, dao.ErrRevisionNotFound)
+	mustGet(t, d, "u1", second.ID)
+	if revisions, _ := d.ListRevisions(ctx, "u1", second.ID); len(revisions) != 1 {
+		t.Fatalf("未删除的简历的修订 = %+v", revisions)
+	}
+}
+
+func testRevisions(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+	r.BasicInfo[0].Name = "张三丰"
+	if err := d.Update(ctx, r, domain.SourceAIGenerate); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	// 修订号在用户维度内递增
+	other := mustCreate(t, d, newResume("u1", "张三"))
+
+	revisions, err := d.ListRevisions(ctx, "u1", r.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revisions) != 2 || revisions[0].Revision != 2 || revisions[0].Source != domain.SourceAIGenerate ||
+		revisions[1].Revision != 1 || revisions[1].Source != domain.SourceManual || revisions[0].Resume != nil {
+		t.Fatalf("ListRevisions = %+v", revisions)
+	}
+
+	rev, err := d.GetRevision(ctx, "u1", 1)
+	if err != nil {
+		t.Fatalf("GetRevision: %v", err)
+	}
+	if rev.ResumeID != r.ID || rev.Resume == nil || rev.Resume.BasicInfo[0].Name != "张三" {
+		t.Fatalf("GetRevision(1) = %+v", rev)
+	}
+	rev, err = d.GetRevision(ctx, "u1", 3)
+	if err != nil || rev.ResumeID != other.ID {
+		t.Fatalf("GetRevision(3) = %+v, %v", rev, err)
+	}
+	_, err = d.GetRevision(ctx, "u2", 1)
+	wantErr(t, "读取其他用户的修订", err, dao.ErrRevisionNotFound)
+	_, err = d.GetRevision(ctx, "u1", 99)
+	wantErr(t, "读取不存在的修订", err, dao.ErrRevisionNotFound)
+}
+
+// testRevisionsAfterPurge 永久删除简历及其修订后，修订号不会被重新使用
+func testRevisionsAfterPurge(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+	r.Summary = "五年后端经验"
+	if err := d.Update(ctx, r, domain.SourceManual); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if err := d.Delete(ctx, "u1", r.ID); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if n, err := d.Purge(ctx, time.Now().Add(time.Second)); err != nil || n != 1 {
+		t.Fatalf("Purge = %d, %v", n, err)
+	}
+
+	next := mustCreate(t, d, newResume("u1", "张三"))
+	revisions, err := d.ListRevisions(ctx, "u1", next.ID)
+	if err != nil {
+		t.Fatalf("ListRevisions: %v", err)
+	}
+	if len(revisions) != 1 || revisions[0].Revision != 3 {
+		t.Fatalf("ListRevisions = %+v，期望修订号 3（1、2 已被永久删除的简历使用）", revisions)
+	}
+	_, err = d.GetRevision(ctx, "u1", 1)
+	wantErr(t, "读取已永久删除的简历的修订", err, dao.ErrRevisionNotFound)
+}
+
+// testConcurrentRevisions 同一用户并发修改不同简历时，修订号不重复也不出错
+func testConcurrentRevisions(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	resumes := []*domain.Resume{
+		mustCreate(t, d, newResume("u1", "张三")),
+		mustCreate(t, d, newResume("u1", "张三")),
+	}
+
+	const writes = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, writes*len(resumes))
+	for _, r := range resumes {
+		for i := 0; i < writes; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := d.Modify(ctx, "u1", r.ID, domain.SourceManual, func(r *domain.Resume) error {
+					r.Skills = append(r.Skills, "Redis")
+					return nil
+				})
+				errs <- err
+			}()
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Modify: %v", err)
+		}
+	}
+
+	seen := map[int]bool{}
+	for _, r := range resumes {
+		revisions, err := d.ListRevisions(ctx, "u1", r.ID)
+		if err != nil {
+			t.Fatalf("ListRevisions: %v", err)
+		}
+		for _, rev := range revisions {
+			if seen[rev.Revision] {
+				t.Fatalf("修订号 %d 重复", rev.Revision)
+			}
+			seen[rev.Revision] = true
+		}
+	}
+	if want := len(resumes) * (writes + 1); len(seen) != want {
+		t.Fatalf("共 %d 条修订，期望 %d", len(seen), want)
+	}
+}
+
+// testConcurrentCreate 同一用户并发创建第一份简历时都能成功，且只有一份成为默认简历
+func testConcurrentCreate(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	const creates = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, creates)
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- d.Create(ctx, newResume("u1", "张三"), domain.SourceManual)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	list, err := d.List(ctx, "u1")
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	defaults := 0
+	for _, s := range list {
+		if s.IsDefault {
+			defaults++
+		}
+	}
+	if len(list) != creates || defaults != 1 {
+		t.Fatalf("List = %+v，期望 %d 份简历且只有一份默认简历", list, creates)
+	}
+}
+
+func testJobIdempotency(t *testing.T, d dao.ResumeDAO) {
+	ctx := context.Background()
+	r := mustCreate(t, d, newResume("u1", "张三"))
+
+	rev, err := d.GetRevisionByJob(ctx, "job-1")
+	if err != nil || rev != nil {
+		t.Fatalf("任务尚未写入时 GetRevisionByJob = %+v, %v", rev, err)
+	}
+
+	jobCtx := dao.WithJobID(ctx, "job-1")
+	update := newResume("u1", "张三丰")
+	if err := d.Update(jobCtx, update, domain.SourceAIGenerate); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	retry := newResume("u1", "李四")
+	wantErr(t, "同一任务重复 Update", d.Update(jobCtx, retry, domain.SourceAIGenerate), dao.ErrJobAlreadyApplied)
+	wantErr(t, "同一任务重复 Create", d.Create(jobCtx, newResume("u1", "李四"), domain.SourceAIGenerate), dao.ErrJobAlreadyApplied)
+
+	if got := mustGet(t, d, "u1", r.ID); got.Version != 2 || got.BasicInfo[0].Name != "张三丰" {
+		t.Fatalf("重复写入后内容被修改: %+v", got)
+	}
+	if list, _ := d.List(ctx, "u1"); len(list) != 1 {
+		t.Fatalf("重复 Create 后有 %d 份简历", len(list))
+	}
+
+	rev, err = d.GetRevisionByJob(ctx, "job-1")
+	if err != nil || rev == nil || rev.Revision != 2 || rev.Resume.BasicInfo[0].Name != "张三丰" {
+		t.Fatalf("GetRevisionByJob = %+v, %v", rev, err)
+	}
+}
@@ -0,0 +1,392 @@
+package dao
+
+import (
+	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/model"
+	"context"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryDAO 进程内存储，与数据库实现使用相同的模型和转换，行为一致。
+// 数据在重启后丢失，只用于本地演示和测试
+type memoryDAO struct {
+	mu        sync.Mutex
+	resumes   map[string]*model.ResumeModel // 键为简历ID
+	revisions []model.ResumeRevisionModel
+	// counters 每个用户最近分配的修订号，与数据库实现一样不随简历永久删除而回退
+	counters map[string]int
+}
+
+// NewMemoryDAO 创建空的进程内存储
+func NewMemoryDAO() ResumeDAO {
+	return &memoryDAO{resumes: map[string]*model.ResumeModel{}, counters: map[string]int{}}
+}
+
+// resolveID 将空的 resumeID 解析为用户默认简历的ID，调用方需持有锁
+func (d *memoryDAO) resolveID(userID, resumeID string) (string, error) {
+	if resumeID != "" {
+		return resumeID, nil
+	}
+	for _, m := range d.resumes {
+		if m.UserID == userID && m.IsDefault && !m.DeletedAt.Valid {
+			return m.ResumeID, nil
+		}
+	}
+	return "", ErrNotFound
+}
+
+// find 查询属于该用户且不在回收站中的简历记录，调用方需持有锁
+func (d *memoryDAO) find(userID, resumeID string) (*model.ResumeModel, error) {
+	id, err := d.resolveID(userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	m, ok := d.resumes[id]
+	if !ok || m.UserID != userID || m.DeletedAt.Valid {
+		return nil, ErrNotFound
+	}
+	return m, nil
+}
+
+// userResumes 用户不在回收站中的全部简历记录，按更新时间倒序，调用方需持有锁
+func (d *memoryDAO) userResumes(userID string) []*model.ResumeModel {
+	var rows []*model.ResumeModel
+	for _, m := range d.resumes {
+		if m.UserID == userID && !m.DeletedAt.Valid {
+			rows = append(rows, m)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].UpdatedAt.After(rows[j].UpdatedAt)
+	})
+	return rows
+}
+
+// newRevision 为简历生成下一条修订并占用修订号，调用方需持有锁，且之后不能再失败；
+// 该异步任务已写入过时返回 ErrJobAlreadyApplied，调用方此时不应做任何修改
+func (d *memoryDAO) newRevision(ctx context.Context, r *domain.Resume, source domain.RevisionSource) (*model.ResumeRevisionModel, error) {
+	jobID := jobIDFrom(ctx)
+	if jobID != nil {
+		for _, rev := range d.revisions {
+			if rev.JobID != nil && *rev.JobID == *jobID {
+				return nil, ErrJobAlreadyApplied
+			}
+		}
+	}
+
+	snapshot, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	d.counters[r.UserID]++
+	return &model.ResumeRevisionModel{
+		UserID:    r.UserID,
+		ResumeID:  r.ID,
+		Revision:  d.counters[r.UserID],
+		Source:    string(source),
+		JobID:     jobID,
+		Snapshot:  snapshot,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+func (d *memoryDAO) Create(ctx context.Context, r *domain.Resume, source domain.RevisionSource) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if r.ID == "" {
+		r.ID = uuid.NewString()
+	}
+	if r.Name == "" {
+		r.Name = domain.DefaultResumeName
+	}
+	domain.AssignItemIDs(r, nil)
+	r.IsDefault = len(d.userResumes(r.UserID)) == 0
+	r.Version = 1
+	r.CreatedAt = time.Now()
+	r.UpdatedAt = r.CreatedAt
+
+	m, err := domainToModel(r)
+	if err != nil {
+		return err
+	}
+	m.CreatedAt, m.UpdatedAt = r.CreatedAt, r.UpdatedAt
+	rev, err := d.newRevision(ctx, r, source)
+	if err != nil {
+		return err
+	}
+
+	d.resumes[r.ID] = m
+	d.revisions = append(d.revisions, *rev)
+	return nil
+}
+
+func (d *memoryDAO) Get(_ context.Context, userID, resumeID string) (*domain.Resume, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m, err := d.find(userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	return modelToDomain(m)
+}
+
+func (d *memoryDAO) List(_ context.Context, userID string) ([]domain.ResumeSummary, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	rows := d.userResumes(userID)
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].IsDefault && !rows[j].IsDefault
+	})
+
+	return summaries(deref(rows)), nil
+}
+
+// deref 复制记录，避免调用方持有存储中的指针
+func deref(rows []*model.ResumeModel) []model.ResumeModel {
+	result := make([]model.ResumeModel, 0, len(rows))
+	for _, m := range rows {
+		result = append(result, *m)
+	}
+	return result
+}
+
+func (d *memoryDAO) Update(ctx context.Context, r *domain.Resume, source domain.RevisionSource) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing, err := d.find(r.UserID, r.ID)
+	if err != nil {
+		return err
+	}
+	return d.save(ctx, existing, r, source)
+}
+
+func (d *memoryDAO) Modify(ctx context.Context, userID, resumeID string, source domain.RevisionSource, fn func(r *domain.Resume) error) (*domain.Resume, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing, err := d.find(userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+	r, err := modelToDomain(existing)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(r); err != nil {
+		return nil, err
+	}
+	if err := d.save(ctx, existing, r, source); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// save 用 r 的内容替换记录 existing 并追加修订，规则与 saveContent 相同，调用方需持有锁
+func (d *memoryDAO) save(ctx context.Context, existing *model.ResumeModel, r *domain.Resume, source domain.RevisionSource) error {
+	if r.Version != 0 && r.Version != existing.Version {
+		return ErrVersionConflict
+	}
+	previous, err := modelToDomain(existing)
+	if err != nil {
+		return err
+	}
+	r.ID, r.UserID, r.Name, r.IsDefault, r.CreatedAt = existing.ResumeID, existing.UserID, existing.Name, existing.IsDefault, existing.CreatedAt
+	r.UpdatedAt = time.Now()
+	r.Version = existing.Version + 1
+	domain.AssignItemIDs(r, previous)
+
+	m, err := domainToModel(r)
+	if err != nil {
+		return err
+	}
+	m.ID, m.CreatedAt, m.UpdatedAt = existing.ID, existing.CreatedAt, r.UpdatedAt
+	rev, err := d.newRevision(ctx, r, source)
+	if err != nil {
+		return err
+	}
+
+	d.resumes[r.ID] = m
+	d.revisions = append(d.revisions, *rev)
+	return nil
+}
+
+// touch 修改元数据后更新版本和更新时间，调用方需持有锁
+func touch(m *model.ResumeModel) {
+	m.Version++
+	m.UpdatedAt = time.Now()
+}
+
+func (d *memoryDAO) Rename(_ context.Context, userID, resumeID, name string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m, err := d.find(userID, resumeID)
+	if err != nil {
+		return err
+	}
+	m.Name = name
+	touch(m)
+	return nil
+}
+
+func (d *memoryDAO) SetDefault(_ context.Context, userID, resumeID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	target, err := d.find(userID, resumeID)
+	if err != nil {
+		return err
+	}
+	for _, m := range d.userResumes(userID) {
+		if m.IsDefault != (m == target) {
+			m.IsDefault, m.DefaultUserID = m == target, defaultUserID(userID, m == target)
+			touch(m)
+		}
+	}
+	return nil
+}
+
+func (d *memoryDAO) Delete(_ context.Context, userID, resumeID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	existing, err := d.find(userID, resumeID)
+	if err != nil {
+		return err
+	}
+	// 移入回收站：取消默认标记，恢复时再决定是否成为默认简历
+	wasDefault := existing.IsDefault
+	existing.IsDefault, existing.DefaultUserID = false, nil
+	touch(existing)
+	existing.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	if !wasDefault {
+		return nil
+	}
+
+	// 由最近更新的另一份简历接替默认
+	if rows := d.userResumes(userID); len(rows) > 0 {
+		rows[0].IsDefault, rows[0].DefaultUserID = true, defaultUserID(userID, true)
+		touch(rows[0])
+	}
+	return nil
+}
+
+func (d *memoryDAO) ListTrash(_ context.Context, userID string) ([]domain.ResumeSummary, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var rows []model.ResumeModel
+	for _, m := range d.resumes {
+		if m.UserID == userID && m.DeletedAt.Valid {
+			rows = append(rows, *m)
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool {
+		return rows[i].DeletedAt.Time.After(rows[j].DeletedAt.Time)
+	})
+	return summaries(rows), nil
+}
+
+func (d *memoryDAO) Restore(_ context.Context, userID, resumeID string) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	m, ok := d.resumes[resumeID]
+	if !ok || m.UserID != userID || !m.DeletedAt.Valid {
+		return ErrNotFound
+	}
+	isDefault := len(d.userResumes(userID)) == 0
+	m.DeletedAt = gorm.DeletedAt{}
+	m.IsDefault, m.DefaultUserID = isDefault, defaultUserID(userID, isDefault)
+	touch(m)
+	return nil
+}
+
+func (d *memoryDAO) Purge(_ context.Context, before time.Time) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	purged := map[string]bool{}
+	for id, m := range d.resumes {
+		if m.DeletedAt.Valid && m.DeletedAt.Time.Before(before) {
+			purged[id] = true
+			delete(d.resumes, id)
+		}
+	}
+	if len(purged) == 0 {
+		return 0, nil
+	}
+
+	revisions := d.revisions[:0]
+	for _, rev := range d.revisions {
+		if !purged[rev.ResumeID] {
+			revisions = append(revisions, rev)
+		}
+	}
+	d.revisions = revisions
+	return len(purged), nil
+}
+
+func (d *memoryDAO) ListRevisions(_ context.Context, userID, resumeID string) ([]domain.Revision, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	id, err := d.resolveID(userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := []domain.Revision{}
+	for i := len(d.revisions) - 1; i >= 0; i-- {
+		row := d.revisions[i]
+		if row.UserID != userID || row.ResumeID != id {
+			continue
+		}
+		revisions = append(revisions, domain.Revision{
+			UserID:    row.UserID,
+			ResumeID:  row.ResumeID,
+			Revision:  row.Revision,
+			Source:    domain.RevisionSource(row.Source),
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return revisions, nil
+}
+
+func (d *memoryDAO) GetRevision(_ context.Context, userID string, revision int) (*domain.Revision, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, row := range d.revisions {
+		if row.UserID == userID && row.Revision == revision {
+			return revisionFromModel(&row)
+		}
+	}
+	return nil, ErrRevisionNotFound
+}
+
+func (d *memoryDAO) GetRevisionByJob(_ context.Context, jobID string) (*domain.Revision, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, row := range d.revisions {
+		if row.JobID != nil && *row.JobID == jobID {
+			return revisionFromModel(&row)
+		}
+	}
+	return nil, nil
+}
+
+func (d *memoryDAO) Close() error {
+	return nil
+}
@@ -0,0 +1,202 @@
+package dao
+
+import (
+	"ResumeBuilder/internal/cache"
+	"ResumeBuilder/internal/config"
+	"context"
+	"errors"
+	"expvar"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// 缓存中的特殊值
+const (
+	// tombstone 数据刚被修改：读取时视为未命中，且在其过期前不回填缓存，
+	// 防止修改前从数据库读到旧数据的并发请求把旧内容写回缓存
+	tombstone = "__tombstone__"
+	// notFound 负缓存：简历不存在或用户没有默认简历
+	notFound = "__not_found__"
+)
+
+// maxPendingInvalidations 最多记录多少个失效失败、待补写墓碑的键
+const maxPendingInvalidations = 10000
+
+// cacheStats 缓存指标，开启 HTTP_DEBUG_VARS 后通过 /debug/vars 的 resume_cache 查看：
+// hits 命中，misses 未命中，negative_hits 命中负缓存，tombstones 读到墓碑，
+// loads 从数据库加载，coalesced 与其他请求合并的加载，errors 缓存读写失败
+var cacheStats = expvar.NewMap("resume_cache")
+
+// resumeCache 简历缓存：未命中时合并并发加载，写入数据库后以墓碑使缓存失效，不存在的结果短暂缓存。
+// 缓存不可用时直接读写数据库；失效失败的键记录在 pending 中，缓存恢复后先补写墓碑再使用这些键
+type resumeCache struct {
+	store cache.Cache
+	cfg   config.CacheConfig
+	group singleflight.Group
+
+	mu       sync.Mutex
+	pending  map[string]struct{}
+	flushing sync.Mutex // 同一时间只有一个请求补写墓碑
+}
+
+func newResumeCache(store cache.Cache, cfg config.CacheConfig) *resumeCache {
+	return &resumeCache{store: store, cfg: cfg, pending: map[string]struct{}{}}
+}
+
+// load 读取 key 的缓存值，未命中时调用 fetch 从数据库加载并回填。
+// 同一个 key 的并发加载只执行一次 fetch；fetch 返回 ErrNotFound 时写入负缓存。
+// 读到墓碑、缓存出错或 key 的失效尚未补写成功时直接从数据库加载且不回填：
+// 此时可能有写入前发起、读到旧数据的加载仍在进行，不能与之合并
+func (c *resumeCache) load(ctx context.Context, key string, fetch func(ctx context.Context) (string, error)) (string, error) {
+	// 失效尚未补写成功的键视同墓碑
+	val, err := tombstone, error(nil)
+	if !c.stale(ctx, key) {
+		val, err = c.store.Get(ctx, key)
+	}
+	switch {
+	case err == nil && val == notFound:
+		cacheStats.Add("negative_hits", 1)
+		return "", ErrNotFound
+	case err == nil && val == tombstone:
+		cacheStats.Add("tombstones", 1)
+		cacheStats.Add("loads", 1)
+		return fetch(ctx)
+	case err == nil:
+		cacheStats.Add("hits", 1)
+		return val, nil
+	case errors.Is(err, cache.ErrMiss):
+		cacheStats.Add("misses", 1)
+	default:
+		cacheStats.Add("errors", 1)
+		cacheStats.Add("loads", 1)
+		return fetch(ctx)
+	}
+
+	ch := c.group.DoChan(key, func() (any, error) {
+		cacheStats.Add("loads", 1)
+		// 加载结果由所有等待的请求共享，不随发起者的取消而中止，但仍受其处理时限约束
+		loadCtx := context.WithoutCancel(ctx)
+		if deadline, ok := ctx.Deadline(); ok {
+			var cancel context.CancelFunc
+			loadCtx, cancel = context.WithDeadline(loadCtx, deadline)
+			defer cancel()
+		}
+
+		val, err := fetch(loadCtx)
+		switch {
+		case err == nil:
+			c.fill(loadCtx, key, val, c.ttl())
+		case errors.Is(err, ErrNotFound) && c.cfg.NegativeTTL > 0:
+			c.fill(loadCtx, key, notFound, c.cfg.NegativeTTL)
+		}
+		return val, err
+	})
+
+	select {
+	case res := <-ch:
+		if res.Shared {
+			cacheStats.Add("coalesced", 1)
+		}
+		if res.Err != nil {
+			return "", res.Err
+		}
+		return res.Val.(string), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// fill 回填缓存，键已存在（如墓碑）时不覆盖
+func (c *resumeCache) fill(ctx context.Context, key, val string, ttl time.Duration) {
+	if err := c.store.SetNX(ctx, key, val, ttl); err != nil {
+		cacheStats.Add("errors", 1)
+	}
+}
+
+// invalidate 将 keys 标记为墓碑。数据库写入前后各调用一次：
+// 写入后的标记失败时，写入前的墓碑过期后读取也会从数据库加载到新数据。
+// 两次都失败（如 Redis 不可用）的键记入 pending，缓存恢复后补写
+func (c *resumeCache) invalidate(ctx context.Context, keys ...string) {
+	if len(keys) == 0 {
+		return
+	}
+	c.flush(ctx)
+	for i, key := range keys {
+		if err := c.store.Set(ctx, key, tombstone, c.cfg.TombstoneTTL); err != nil {
+			cacheStats.Add("errors", 1)
+			c.postpone(keys[i:], err)
+			return
+		}
+	}
+}
+
+// postpone 记录失效失败的键，超出上限的键只能等缓存自然过期
+func (c *resumeCache) postpone(keys []string, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dropped := 0
+	for _, key := range keys {
+		if len(c.pending) >= maxPendingInvalidations {
+			dropped++
+			continue
+		}
+		c.pending[key] = struct{}{}
+	}
+	if dropped > 0 {
+		log.Printf("⚠️ 简历缓存失效失败，%d 个键最长 %v 内可能读到旧数据: %v\n", dropped, c.cfg.TTL+c.cfg.TTLJitter, err)
+	}
+}
+
+// stale 判断 key 的失效是否尚未补写成功，此时缓存中可能是旧数据。
+// 有待补写的键时先尝试补写
+func (c *resumeCache) stale(ctx context.Context, key string) bool {
+	c.mu.Lock()
+	n := len(c.pending)
+	c.mu.Unlock()
+	if n == 0 {
+		return false
+	}
+
+	c.flush(ctx)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.pending[key]
+	return ok
+}
+
+// flush 为 pending 中的键补写墓碑，遇到错误即停止，留待下次重试
+func (c *resumeCache) flush(ctx context.Context) {
+	if !c.flushing.TryLock() {
+		return
+	}
+	defer c.flushing.Unlock()
+
+	c.mu.Lock()
+	keys := make([]string, 0, len(c.pending))
+	for key := range c.pending {
+		keys = append(keys, key)
+	}
+	c.mu.Unlock()
+
+	for _, key := range keys {
+		if err := c.store.Set(ctx, key, tombstone, c.cfg.TombstoneTTL); err != nil {
+			return
+		}
+		c.mu.Lock()
+		delete(c.pending, key)
+		c.mu.Unlock()
+	}
+}
+
+// ttl 缓存有效期，加上随机抖动避免同时写入的缓存同时过期
+func (c *resumeCache) ttl() time.Duration {
+	if c.cfg.TTLJitter <= 0 {
+		return c.cfg.TTL
+	}
+	return c.cfg.TTL + rand.N(c.cfg.TTLJitter)
+}
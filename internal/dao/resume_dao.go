@@ -1,68 +1,168 @@
 package dao
 
 import (
+	"ResumeBuilder/internal/cache"
+	"ResumeBuilder/internal/config"
 	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/migration"
 	"ResumeBuilder/internal/model"
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
 	"gorm.io/gorm/schema"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// ResumeDAO 简历存储。一个用户可以拥有多份简历，其中一份为默认简历；
+// 参数 resumeID 为空时表示该用户的默认简历
 type ResumeDAO interface {
-	Create(ctx context.Context, r *domain.Resume) error
-	Get(ctx context.Context, userID string) (*domain.Resume, error)
-	Update(ctx context.Context, r *domain.Resume) error
-	Delete(ctx context.Context, userID string) error
+	// Create 创建简历，r.ID 为空时自动生成；用户的第一份简历自动成为默认简历
+	// Create 和 Update 在同一事务中追加一条来源为 source 的修订记录
+	Create(ctx context.Context, r *domain.Resume, source domain.RevisionSource) error
+	Get(ctx context.Context, userID, resumeID string) (*domain.Resume, error)
+	// List 返回用户的全部简历概要，默认简历排在最前
+	List(ctx context.Context, userID string) ([]domain.ResumeSummary, error)
+	// Update 更新简历内容（不修改名称和默认标记），r.ID 为空时更新默认简历。
+	// r.Version 不为 0 时仅当它等于简历的当前版本才更新，否则返回 ErrVersionConflict；
+	// 成功后 r.Version 为新版本。Create、Update、Modify、Rename 和 SetDefault 都会使版本加一
+	Update(ctx context.Context, r *domain.Resume, source domain.RevisionSource) error
+	// Modify 在同一事务中锁定并读取简历，交给 fn 修改内容后写回，并追加来源为 source 的修订。
+	// fn 返回错误时不做任何修改，错误原样返回；fn 对名称、默认标记等元数据的修改会被忽略，
+	// fn 可以通过比较 r.Version 实现条件修改
+	Modify(ctx context.Context, userID, resumeID string, source domain.RevisionSource, fn func(r *domain.Resume) error) (*domain.Resume, error)
+	Rename(ctx context.Context, userID, resumeID, name string) error
+	SetDefault(ctx context.Context, userID, resumeID string) error
+	// Delete 将简历移入回收站，删除默认简历时由最近更新的另一份简历接替默认。
+	// 回收站中的简历对其他方法均不可见
+	Delete(ctx context.Context, userID, resumeID string) error
+	// ListTrash 返回回收站中的简历概要，最近删除的排在最前
+	ListTrash(ctx context.Context, userID string) ([]domain.ResumeSummary, error)
+	// Restore 从回收站恢复简历，简历不在回收站中时返回 ErrNotFound；
+	// 用户没有其他简历时恢复的简历成为默认简历，版本加一
+	Restore(ctx context.Context, userID, resumeID string) error
+	// Purge 永久删除在 before 之前移入回收站的简历及其修订，返回删除的简历数
+	Purge(ctx context.Context, before time.Time) (int, error)
+
+	// ListRevisions 按修订号倒序返回简历的修订列表（不含简历内容）
+	ListRevisions(ctx context.Context, userID, resumeID string) ([]domain.Revision, error)
+	// GetRevision 返回指定修订号的完整修订
+	GetRevision(ctx context.Context, userID string, revision int) (*domain.Revision, error)
+	// GetRevisionByJob 返回异步任务 jobID 写入的修订，任务尚未写入时返回 nil, nil
+	GetRevisionByJob(ctx context.Context, jobID string) (*domain.Revision, error)
+
+	// Close 关闭数据库连接池。缓存由调用方创建，也由调用方关闭
+	Close() error
 }
 
 type resumeDAO struct {
 	db    *gorm.DB
-	redis *redis.Client
+	cache *resumeCache
 }
 
-func NewResumeDAO() ResumeDAO {
-	dbUrl := "root:xkw510724@tcp(127.0.0.1:3306)/resume_builder?charset=utf8&parseTime=true&loc=Local"
-	db, err := gorm.Open(mysql.Open(dbUrl), &gorm.Config{
-		NamingStrategy: schema.NamingStrategy{
-			SingularTable: true,
-		},
+// NewRedisClient 根据配置创建 Redis 客户端，简历缓存与异步任务队列共用同一个实例
+func NewRedisClient(cfg config.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.Addr,
+		Password: cfg.Password,
+		DB:       cfg.DB,
 	})
-	if err != nil {
-		panic(err)
+}
+
+// NewResumeDAO 根据配置选择存储：MySQL、SQLite 使用 store 作为缓存，
+// 进程内存储本身就在内存中，不使用缓存。
+// 数据库结构落后于程序时返回 migration.ErrSchemaBehind，配置了 auto_migrate 时先自动执行迁移
+func NewResumeDAO(dbCfg config.DBConfig, store cache.Cache, cacheCfg config.CacheConfig) (ResumeDAO, error) {
+	if dbCfg.Driver == "memory" {
+		return NewMemoryDAO(), nil
 	}
-	err = db.AutoMigrate(model.ResumeModel{})
+
+	db, err := OpenDB(dbCfg)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	ctx := context.Background()
+	if dbCfg.AutoMigrate {
+		if _, err := migration.Up(ctx, db); err != nil {
+			return nil, fmt.Errorf("数据库迁移失败: %w", err)
+		}
+	}
+	if err := migration.Check(ctx, db); err != nil {
+		return nil, err
 	}
-
-	client := redis.NewClient(&redis.Options{
-		Addr:     "127.0.0.1:6379",
-		Password: "",
-		DB:       0,
-	})
 
 	return &resumeDAO{
 		db:    db,
-		redis: client,
+		cache: newResumeCache(store, cacheCfg),
+	}, nil
+}
+
+// OpenDB 根据配置连接 MySQL 或 SQLite，不检查数据库结构
+func OpenDB(cfg config.DBConfig) (*gorm.DB, error) {
+	var dialector gorm.Dialector
+	switch cfg.Driver {
+	case "mysql":
+		dialector = mysql.Open(cfg.DSN)
+	case "sqlite":
+		dialector = sqlite.Open(cfg.DSN)
+	default:
+		return nil, fmt.Errorf("存储 %s 不使用数据库", cfg.Driver)
 	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("连接数据库失败: %w", err)
+	}
+	if cfg.Driver == "sqlite" {
+		// SQLite 同一时间只允许一个写事务，共用一个连接让事务依次执行，避免 database is locked
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("连接数据库失败: %w", err)
+		}
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return db, nil
+}
+
+// cacheKey 单份简历的缓存键
+func cacheKey(resumeID string) string {
+	return "resume:" + resumeID
 }
 
-func redisKey(userID string) string {
-	return "resume:" + userID
+// defaultCacheKey 用户默认简历ID的缓存键
+func defaultCacheKey(userID string) string {
+	return "resume:default:" + userID
 }
 
-var cacheTTL = time.Minute * 10
+// defaultUserID 记录的 default_user_id 列：默认简历为用户ID，其余为 NULL
+func defaultUserID(userID string, isDefault bool) *string {
+	if !isDefault {
+		return nil
+	}
+	return &userID
+}
 
 func domainToModel(r *domain.Resume) (*model.ResumeModel, error) {
 	m := &model.ResumeModel{
-		UserID: r.UserID,
+		ResumeID:  r.ID,
+		UserID:    r.UserID,
+		Name:      r.Name,
+		IsDefault: r.IsDefault,
+		Version:   r.Version,
 	}
+	m.DefaultUserID = defaultUserID(r.UserID, r.IsDefault)
 
 	// 结构体 -> JSON
 	if b, err := json.Marshal(r.BasicInfo); err == nil {
@@ -95,126 +195,486 @@ func domainToModel(r *domain.Resume) (*model.ResumeModel, error) {
 		return nil, err
 	}
 
+	m.Summary = r.Summary
+	sections := []struct {
+		dst *datatypes.JSON
+		src any
+	}{
+		{&m.Certifications, r.Certifications},
+		{&m.Awards, r.Awards},
+		{&m.Languages, r.Languages},
+		{&m.Links, r.Links},
+		{&m.Publications, r.Publications},
+		{&m.CustomSections, r.CustomSections},
+		{&m.SkillIDs, r.SkillIDs},
+	}
+	for _, s := range sections {
+		b, err := json.Marshal(s.src)
+		if err != nil {
+			return nil, err
+		}
+		*s.dst = b
+	}
+
 	return m, nil
 }
 
 func modelToDomain(m *model.ResumeModel) (*domain.Resume, error) {
 	r := &domain.Resume{
-		UserID: m.UserID,
+		ID:        m.ResumeID,
+		UserID:    m.UserID,
+		Name:      m.Name,
+		IsDefault: m.IsDefault,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+		Version:   m.Version,
+		Summary:   m.Summary,
 	}
 
-	// JSON -> 结构体
-	if err := json.Unmarshal(m.BasicInfo, &r.BasicInfo); err != nil {
-		return nil, err
-	}
-	if err := json.Unmarshal(m.Education, &r.Education); err != nil {
-		return nil, err
+	// JSON -> 结构体；列为 NULL（如迁移 5、6、8 之前保存的简历）时章节为空
+	sections := []struct {
+		src datatypes.JSON
+		dst any
+	}{
+		{m.BasicInfo, &r.BasicInfo},
+		{m.Education, &r.Education},
+		{m.Experience, &r.Experience},
+		{m.Projects, &r.Projects},
+		{m.Skills, &r.Skills},
+		{m.Certifications, &r.Certifications},
+		{m.Awards, &r.Awards},
+		{m.Languages, &r.Languages},
+		{m.Links, &r.Links},
+		{m.Publications, &r.Publications},
+		{m.CustomSections, &r.CustomSections},
+		{m.SkillIDs, &r.SkillIDs},
 	}
-	if err := json.Unmarshal(m.Experience, &r.Experience); err != nil {
-		return nil, err
+	for _, s := range sections {
+		if len(s.src) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(s.src, s.dst); err != nil {
+			return nil, err
+		}
 	}
-	if err := json.Unmarshal(m.Projects, &r.Projects); err != nil {
-		return nil, err
+	domain.AssignLegacyIDs(r)
+
+	return r, nil
+}
+
+// resolveID 将空的 resumeID 解析为用户默认简历的ID
+func (d *resumeDAO) resolveID(ctx context.Context, userID, resumeID string) (string, error) {
+	if resumeID != "" {
+		return resumeID, nil
 	}
-	if err := json.Unmarshal(m.Skills, &r.Skills); err != nil {
+
+	return d.cache.load(ctx, defaultCacheKey(userID), func(ctx context.Context) (string, error) {
+		var m model.ResumeModel
+		if err := d.db.WithContext(ctx).Select("resume_id").
+			Where("user_id = ? AND is_default = ?", userID, true).
+			First(&m).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrNotFound
+			}
+			return "", err
+		}
+		return m.ResumeID, nil
+	})
+}
+
+// findModel 查询属于该用户的简历记录，不存在时返回 ErrNotFound
+func (d *resumeDAO) findModel(tx *gorm.DB, userID, resumeID string) (*model.ResumeModel, error) {
+	var m model.ResumeModel
+	if err := tx.Where("user_id = ? AND resume_id = ?", userID, resumeID).First(&m).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
 		return nil, err
 	}
+	return &m, nil
+}
 
-	return r, nil
+// afterCommit 返回提交后维护缓存所用的 ctx：数据库已写入，即使请求已取消也要使缓存失效，避免读到旧数据
+func afterCommit(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
 }
 
-func (d *resumeDAO) Create(ctx context.Context, r *domain.Resume) error {
-	m, err := domainToModel(r)
-	if err != nil {
-		return err
+// userKeys 用户全部简历及默认简历指针的缓存键，用于默认简历发生变化时
+func (d *resumeDAO) userKeys(ctx context.Context, userID string) []string {
+	var ids []string
+	d.db.WithContext(ctx).Model(&model.ResumeModel{}).Where("user_id = ?", userID).Pluck("resume_id", &ids)
+
+	keys := []string{defaultCacheKey(userID)}
+	for _, id := range ids {
+		keys = append(keys, cacheKey(id))
 	}
+	return keys
+}
 
-	if err := d.db.Create(m).Error; err != nil {
-		return err
+func (d *resumeDAO) Create(ctx context.Context, r *domain.Resume, source domain.RevisionSource) error {
+	if r.ID == "" {
+		r.ID = uuid.NewString()
+	}
+	if r.Name == "" {
+		r.Name = domain.DefaultResumeName
 	}
+	domain.AssignItemIDs(r, nil)
+
+	// 以指定ID创建（如恢复已删除的简历）时可能有负缓存；用户的第一份简历会成为默认简历
+	keys := []string{cacheKey(r.ID), defaultCacheKey(r.UserID)}
+	d.cache.invalidate(ctx, keys...)
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 用户的第一份简历自动成为默认简历。先锁定用户，避免并发创建的两份简历都成为默认简历
+		if _, err := lockUser(tx, r.UserID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&model.ResumeModel{}).Where("user_id = ?", r.UserID).Count(&count).Error; err != nil {
+			return err
+		}
+		r.IsDefault = count == 0
+		r.Version = 1
 
-	// 写缓存
-	data, _ := json.Marshal(r)
-	d.redis.Set(ctx, redisKey(r.UserID), data, cacheTTL)
+		m, err := domainToModel(r)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		r.CreatedAt, r.UpdatedAt = m.CreatedAt, m.UpdatedAt
+
+		return appendRevision(ctx, tx, r, source)
+	})
+	if err != nil {
+		return err
+	}
 
+	d.cache.invalidate(afterCommit(ctx), keys...)
 	return nil
 }
 
-func (d *resumeDAO) Get(ctx context.Context, userID string) (*domain.Resume, error) {
-	key := redisKey(userID)
+func (d *resumeDAO) Get(ctx context.Context, userID, resumeID string) (*domain.Resume, error) {
+	id, err := d.resolveID(ctx, userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 缓存按简历ID存取（不区分用户），读取后再检查简历是否属于该用户
+	val, err := d.cache.load(ctx, cacheKey(id), func(ctx context.Context) (string, error) {
+		var m model.ResumeModel
+		if err := d.db.WithContext(ctx).Where("resume_id = ?", id).First(&m).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return "", ErrNotFound
+			}
+			return "", err
+		}
 
-	// 读缓存
-	if val, err := d.redis.Get(ctx, key).Result(); err == nil {
-		var r domain.Resume
-		if err2 := json.Unmarshal([]byte(val), &r); err2 == nil {
-			return &r, nil
+		// model → domain
+		r, err := modelToDomain(&m)
+		if err != nil {
+			return "", err
 		}
+		data, err := json.Marshal(r)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 读 MySQL
-	var m model.ResumeModel
-	if err := d.db.Where("user_id = ?", userID).First(&m).Error; err != nil {
+	var r domain.Resume
+	if err := json.Unmarshal([]byte(val), &r); err != nil {
 		return nil, err
 	}
+	if r.UserID != userID {
+		return nil, ErrNotFound
+	}
+	domain.AssignLegacyIDs(&r)
+	return &r, nil
+}
 
-	// model → domain
-	r, err := modelToDomain(&m)
-	if err != nil {
+func (d *resumeDAO) List(ctx context.Context, userID string) ([]domain.ResumeSummary, error) {
+	var rows []model.ResumeModel
+	if err := d.db.WithContext(ctx).Select("resume_id", "user_id", "name", "is_default", "created_at", "updated_at", "version").
+		Where("user_id = ?", userID).
+		Order("is_default DESC, updated_at DESC").
+		Find(&rows).Error; err != nil {
 		return nil, err
 	}
 
-	// 写缓存
-	data, _ := json.Marshal(r)
-	d.redis.Set(ctx, key, data, cacheTTL)
-
-	return r, nil
+	return summaries(rows), nil
 }
 
-func (d *resumeDAO) Update(ctx context.Context, r *domain.Resume) error {
-	// 先检查记录是否存在
-	var existing model.ResumeModel
-	if err := d.db.Where("user_id = ?", r.UserID).First(&existing).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.New("简历不存在，无法更新")
+// summaries 将简历记录转换为概要
+func summaries(rows []model.ResumeModel) []domain.ResumeSummary {
+	result := make([]domain.ResumeSummary, 0, len(rows))
+	for _, m := range rows {
+		s := domain.ResumeSummary{
+			ID:        m.ResumeID,
+			UserID:    m.UserID,
+			Name:      m.Name,
+			IsDefault: m.IsDefault,
+			CreatedAt: m.CreatedAt,
+			UpdatedAt: m.UpdatedAt,
+			Version:   m.Version,
+		}
+		if m.DeletedAt.Valid {
+			s.DeletedAt = &m.DeletedAt.Time
 		}
+		result = append(result, s)
+	}
+	return result
+}
+
+func (d *resumeDAO) Update(ctx context.Context, r *domain.Resume, source domain.RevisionSource) error {
+	id, err := d.resolveID(ctx, r.UserID, r.ID)
+	if err != nil {
 		return err
 	}
 
-	m, err := domainToModel(r)
+	// 先检查记录是否存在
+	existing, err := d.findModel(d.db.WithContext(ctx), r.UserID, id)
 	if err != nil {
 		return err
 	}
+	previous, err := modelToDomain(existing)
+	if err != nil {
+		return err
+	}
+
+	// 更新记录并追加修订；版本冲突时同样使缓存失效，冲突可能源于调用方读到了旧的缓存
+	d.cache.invalidate(ctx, cacheKey(id))
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveContent(ctx, tx, existing, previous, r, source)
+	})
+	d.cache.invalidate(afterCommit(ctx), cacheKey(id))
+	return err
+}
+
+func (d *resumeDAO) Modify(ctx context.Context, userID, resumeID string, source domain.RevisionSource, fn func(r *domain.Resume) error) (*domain.Resume, error) {
+	id, err := d.resolveID(ctx, userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
 
-	// 更新记录
-	if err := d.db.Model(&model.ResumeModel{}).
-		Where("user_id = ?", r.UserID).
-		Updates(m).Error; err != nil {
+	var r *domain.Resume
+	d.cache.invalidate(ctx, cacheKey(id))
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 行锁保证并发的局部修改依次基于最新内容进行，不会互相覆盖
+		existing, err := d.findModel(tx.Clauses(clause.Locking{Strength: "UPDATE"}), userID, id)
+		if err != nil {
+			return err
+		}
+		previous, err := modelToDomain(existing)
+		if err != nil {
+			return err
+		}
+		if r, err = modelToDomain(existing); err != nil {
+			return err
+		}
+
+		if err := fn(r); err != nil {
+			return err
+		}
+		return saveContent(ctx, tx, existing, previous, r, source)
+	})
+	d.cache.invalidate(afterCommit(ctx), cacheKey(id))
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// saveContent 在事务 tx 中用 r 的内容更新记录 existing 并追加修订；
+// 名称、默认标记和创建时间以数据库为准，缺少ID的条目沿用 previous 中同一条目的ID。
+// 仅当数据库中的版本仍是 existing 的版本（且 r.Version 为 0 或与之相同）时才更新，否则返回 ErrVersionConflict
+func saveContent(ctx context.Context, tx *gorm.DB, existing *model.ResumeModel, previous, r *domain.Resume, source domain.RevisionSource) error {
+	if r.Version != 0 && r.Version != existing.Version {
+		return ErrVersionConflict
+	}
+	r.ID, r.UserID, r.Name, r.IsDefault, r.CreatedAt = existing.ResumeID, existing.UserID, existing.Name, existing.IsDefault, existing.CreatedAt
+	r.UpdatedAt = time.Now()
+	r.Version = existing.Version + 1
+	domain.AssignItemIDs(r, previous)
+
+	m, err := domainToModel(r)
+	if err != nil {
 		return err
 	}
+	m.UpdatedAt = r.UpdatedAt
+
+	result := tx.Model(&model.ResumeModel{}).
+		Where("resume_id = ? AND version = ?", existing.ResumeID, existing.Version).
+		Select("basic_info", "summary", "education", "experience", "projects", "skills",
+			"certifications", "awards", "languages", "links", "publications", "custom_sections", "skill_ids", "updated_at", "version").
+		Updates(m)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		// 读取之后被并发修改
+		return ErrVersionConflict
+	}
+	return appendRevision(ctx, tx, r, source)
+}
 
-	// 更新缓存
-	data, _ := json.Marshal(r)
-	d.redis.Set(ctx, redisKey(r.UserID), data, cacheTTL)
+func (d *resumeDAO) Rename(ctx context.Context, userID, resumeID, name string) error {
+	d.cache.invalidate(ctx, cacheKey(resumeID))
+	result := d.db.WithContext(ctx).Model(&model.ResumeModel{}).
+		Where("user_id = ? AND resume_id = ?", userID, resumeID).
+		Updates(map[string]any{"name": name, "version": gorm.Expr("version + 1")})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
 
+	d.cache.invalidate(afterCommit(ctx), cacheKey(resumeID))
 	return nil
 }
 
-func (d *resumeDAO) Delete(ctx context.Context, userID string) error {
-	// 先检查记录是否存在
-	var existing model.ResumeModel
-	if err := d.db.Where("user_id = ?", userID).First(&existing).Error; err != nil {
-		if err == gorm.ErrRecordNotFound {
-			return errors.New("简历不存在，无法删除")
+func (d *resumeDAO) SetDefault(ctx context.Context, userID, resumeID string) error {
+	keys := d.userKeys(ctx, userID)
+	d.cache.invalidate(ctx, keys...)
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := d.findModel(tx, userID, resumeID); err != nil {
+			return err
 		}
+		if err := tx.Model(&model.ResumeModel{}).
+			Where("user_id = ? AND resume_id <> ? AND is_default = ?", userID, resumeID, true).
+			Updates(map[string]any{"is_default": false, "default_user_id": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&model.ResumeModel{}).
+			Where("resume_id = ? AND is_default = ?", resumeID, false).
+			Updates(map[string]any{"is_default": true, "default_user_id": userID, "version": gorm.Expr("version + 1")}).Error
+	})
+	d.cache.invalidate(afterCommit(ctx), keys...)
+	return err
+}
+
+func (d *resumeDAO) Delete(ctx context.Context, userID, resumeID string) error {
+	id, err := d.resolveID(ctx, userID, resumeID)
+	if err != nil {
 		return err
 	}
 
-	// 删除记录
-	if err := d.db.Where("user_id = ?", userID).Delete(&model.ResumeModel{}).Error; err != nil {
+	keys := d.userKeys(ctx, userID)
+	d.cache.invalidate(ctx, keys...)
+	err = d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先检查记录是否存在
+		existing, err := d.findModel(tx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		// 移入回收站：取消默认标记，恢复时再决定是否成为默认简历
+		if err := tx.Model(&model.ResumeModel{}).Where("id = ?", existing.ID).
+			Updates(map[string]any{"is_default": false, "default_user_id": nil, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&model.ResumeModel{}, existing.ID).Error; err != nil {
+			return err
+		}
+		if !existing.IsDefault {
+			return nil
+		}
+
+		// 由最近更新的另一份简历接替默认
+		var next model.ResumeModel
+		err = tx.Where("user_id = ?", userID).Order("updated_at DESC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		return tx.Model(&model.ResumeModel{}).Where("id = ?", next.ID).
+			Updates(map[string]any{"is_default": true, "default_user_id": userID, "version": gorm.Expr("version + 1")}).Error
+	})
+	if err != nil {
 		return err
 	}
 
 	// 删除缓存
-	d.redis.Del(ctx, redisKey(userID))
+	d.cache.invalidate(afterCommit(ctx), keys...)
 	return nil
 }
+
+func (d *resumeDAO) ListTrash(ctx context.Context, userID string) ([]domain.ResumeSummary, error) {
+	var rows []model.ResumeModel
+	if err := d.db.WithContext(ctx).Unscoped().
+		Select("resume_id", "user_id", "name", "is_default", "created_at", "updated_at", "version", "deleted_at").
+		Where("user_id = ? AND deleted_at IS NOT NULL", userID).
+		Order("deleted_at DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return summaries(rows), nil
+}
+
+func (d *resumeDAO) Restore(ctx context.Context, userID, resumeID string) error {
+	// 恢复的简历可能有负缓存，也可能成为默认简历
+	keys := append(d.userKeys(ctx, userID), cacheKey(resumeID))
+	d.cache.invalidate(ctx, keys...)
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var m model.ResumeModel
+		if err := tx.Unscoped().Where("user_id = ? AND resume_id = ? AND deleted_at IS NOT NULL", userID, resumeID).
+			First(&m).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotFound
+			}
+			return err
+		}
+
+		// 与 Create 相同，锁定用户后再判断是否成为默认简历
+		if _, err := lockUser(tx, userID); err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&model.ResumeModel{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&model.ResumeModel{}).Where("id = ?", m.ID).Updates(map[string]any{
+			"deleted_at":      nil,
+			"is_default":      count == 0,
+			"default_user_id": defaultUserID(userID, count == 0),
+			"version":         gorm.Expr("version + 1"),
+		}).Error
+	})
+	d.cache.invalidate(afterCommit(ctx), keys...)
+	return err
+}
+
+func (d *resumeDAO) Purge(ctx context.Context, before time.Time) (int, error) {
+	var ids []string
+	if err := d.db.WithContext(ctx).Unscoped().Model(&model.ResumeModel{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Pluck("resume_id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// 回收站中的简历对读取不可见，缓存中只可能是负缓存，不需要失效
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("resume_id IN ?", ids).Delete(&model.ResumeRevisionModel{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("resume_id IN ? AND deleted_at IS NOT NULL", ids).Delete(&model.ResumeModel{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
+
+func (d *resumeDAO) Close() error {
+	sqlDB, err := d.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
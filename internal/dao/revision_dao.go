@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/model"
+	"context"
+	"encoding/json"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrNotFound 简历不存在（或不属于该用户）
+	ErrNotFound = errors.New("简历不存在")
+	// ErrRevisionNotFound 修订记录不存在
+	ErrRevisionNotFound = errors.New("修订记录不存在")
+	// ErrJobAlreadyApplied 该异步任务的结果已写入过简历，用于保证任务结果只持久化一次
+	ErrJobAlreadyApplied = errors.New("任务结果已写入简历")
+	// ErrVersionConflict 简历在读取之后已被修改，指定的版本不是当前版本
+	ErrVersionConflict = errors.New("简历已被修改，请刷新后重试")
+)
+
+type jobIDKey struct{}
+
+// WithJobID 标记本次写入来自异步任务 jobID。同一任务的写入只会成功一次，
+// 重复写入（如任务重试）时 Create / Update 返回 ErrJobAlreadyApplied 且不做任何修改
+func WithJobID(ctx context.Context, jobID string) context.Context {
+	return context.WithValue(ctx, jobIDKey{}, jobID)
+}
+
+func jobIDFrom(ctx context.Context) *string {
+	if id, ok := ctx.Value(jobIDKey{}).(string); ok && id != "" {
+		return &id
+	}
+	return nil
+}
+
+// appendRevision 在事务中为简历追加一条新修订，修订号在用户维度内递增（跨该用户的所有简历）
+func appendRevision(ctx context.Context, tx *gorm.DB, r *domain.Resume, source domain.RevisionSource) error {
+	snapshot, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	// 来自异步任务的写入：任务ID有唯一索引，这里提前检查以返回明确的错误
+	jobID := jobIDFrom(ctx)
+	if jobID != nil {
+		var count int64
+		if err := tx.Model(&model.ResumeRevisionModel{}).Where("job_id = ?", *jobID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrJobAlreadyApplied
+		}
+	}
+
+	revision, err := nextRevision(tx, r.UserID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&model.ResumeRevisionModel{
+		UserID:   r.UserID,
+		ResumeID: r.ID,
+		Revision: revision,
+		Source:   string(source),
+		JobID:    jobID,
+		Snapshot: snapshot,
+	}).Error
+}
+
+// nextRevision 在事务中为用户分配下一个修订号。锁定用户的计数行直到事务结束，
+// 同一用户的其他写入在此等待，不会读到相同的修订号
+func nextRevision(tx *gorm.DB, userID string) (int, error) {
+	counter, err := lockUser(tx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	counter.LastRevision++
+	if err := tx.Model(counter).Where("user_id = ?", userID).
+		Update("last_revision", counter.LastRevision).Error; err != nil {
+		return 0, err
+	}
+	return counter.LastRevision, nil
+}
+
+// lockUser 锁定用户的修订计数行（不存在时创建）直到事务结束，用于串行化同一用户的写入
+func lockUser(tx *gorm.DB, userID string) (*model.ResumeRevisionCounterModel, error) {
+	counter := model.ResumeRevisionCounterModel{UserID: userID}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&counter).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).First(&counter).Error; err != nil {
+		return nil, err
+	}
+	return &counter, nil
+}
+
+func (d *resumeDAO) ListRevisions(ctx context.Context, userID, resumeID string) ([]domain.Revision, error) {
+	id, err := d.resolveID(ctx, userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []model.ResumeRevisionModel
+	if err := d.db.WithContext(ctx).Select("user_id", "resume_id", "revision", "source", "created_at").
+		Where("user_id = ? AND resume_id = ?", userID, id).
+		Order("revision DESC").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	revisions := make([]domain.Revision, 0, len(rows))
+	for _, row := range rows {
+		revisions = append(revisions, domain.Revision{
+			UserID:    row.UserID,
+			ResumeID:  row.ResumeID,
+			Revision:  row.Revision,
+			Source:    domain.RevisionSource(row.Source),
+			CreatedAt: row.CreatedAt,
+		})
+	}
+	return revisions, nil
+}
+
+func (d *resumeDAO) GetRevision(ctx context.Context, userID string, revision int) (*domain.Revision, error) {
+	var row model.ResumeRevisionModel
+	if err := d.db.WithContext(ctx).Where("user_id = ? AND revision = ?", userID, revision).
+		First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound
+		}
+		return nil, err
+	}
+
+	return revisionFromModel(&row)
+}
+
+func (d *resumeDAO) GetRevisionByJob(ctx context.Context, jobID string) (*domain.Revision, error) {
+	var row model.ResumeRevisionModel
+	if err := d.db.WithContext(ctx).Where("job_id = ?", jobID).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return revisionFromModel(&row)
+}
+
+// revisionFromModel 将修订记录转换为包含完整简历快照的修订
+func revisionFromModel(row *model.ResumeRevisionModel) (*domain.Revision, error) {
+	var r domain.Resume
+	if err := json.Unmarshal(row.Snapshot, &r); err != nil {
+		return nil, err
+	}
+
+	return &domain.Revision{
+		UserID:    row.UserID,
+		ResumeID:  row.ResumeID,
+		Revision:  row.Revision,
+		Source:    domain.RevisionSource(row.Source),
+		CreatedAt: row.CreatedAt,
+		Resume:    &r,
+	}, nil
+}
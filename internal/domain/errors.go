@@ -0,0 +1,39 @@
+package domain
+
+import "strings"
+
+// FieldError 单个字段的校验错误，Field 为字段路径，如 experience[2].end_date
+type FieldError struct {
+	Field string `json:"field,omitempty"`
+	// Rule 未通过的校验规则（如 required、max_length），为空表示 Message 是完整的说明
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+	// Params 规则的参数（如长度上限），用于生成其他语言的 Message
+	Params []any `json:"-"`
+}
+
+func (f FieldError) String() string {
+	// 按规则生成的 Message 不含字段名，拼上路径以便定位
+	if f.Rule != "" && f.Field != "" {
+		return f.Field + ": " + f.Message
+	}
+	return f.Message
+}
+
+// ValidationError 请求参数或简历内容校验失败
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.String())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Invalid 创建单个字段的校验错误，field 为空表示不针对具体字段
+func Invalid(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
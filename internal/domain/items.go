@@ -0,0 +1,396 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// 可按条目编辑的章节
+const (
+	SectionEducation  = "education"
+	SectionExperience = "experience"
+	SectionProjects   = "projects"
+	SectionSkills     = "skills" // 技能为纯文本，条目ID保存在 Resume.SkillIDs 中
+
+	SectionCertifications = "certifications"
+	SectionAwards         = "awards"
+	SectionLanguages      = "languages"
+	SectionLinks          = "links"
+	SectionPublications   = "publications"
+
+	SectionCustom = "custom_sections" // 自定义章节本身，条目为章节
+	// CustomSectionPrefix 加上自定义章节的ID（如 custom:<id>）表示该章节中的条目
+	CustomSectionPrefix = "custom:"
+)
+
+// ErrItemNotFound 章节中不存在该ID的条目
+var ErrItemNotFound = errors.New("条目不存在")
+
+// NewItemID 生成条目ID
+func NewItemID() string {
+	return uuid.NewString()
+}
+
+// AddItem 将 JSON 表示的条目插入 section 的 position 处（越界时追加到末尾），返回新条目的ID。
+// 条目ID由服务端生成，请求中的 id 被忽略
+func (r *Resume) AddItem(section string, item []byte, position int) (string, error) {
+	list, err := r.items(section)
+	if err != nil {
+		return "", err
+	}
+	if position < 0 || position > list.len() {
+		position = list.len()
+	}
+	return list.insert(position, item)
+}
+
+// PatchItem 按 RFC 7396 修改条目；技能条目的补丁为新的文本（JSON 字符串）
+func (r *Resume) PatchItem(section, id string, patch []byte) error {
+	list, err := r.items(section)
+	if err != nil {
+		return err
+	}
+	i, err := indexOf(list, id)
+	if err != nil {
+		return err
+	}
+	return list.patch(i, patch)
+}
+
+// DeleteItem 删除条目
+func (r *Resume) DeleteItem(section, id string) error {
+	list, err := r.items(section)
+	if err != nil {
+		return err
+	}
+	i, err := indexOf(list, id)
+	if err != nil {
+		return err
+	}
+	list.remove(i)
+	return nil
+}
+
+// ReorderItems 按 ids 的顺序重新排列章节，ids 必须恰好包含该章节全部条目的ID
+func (r *Resume) ReorderItems(section string, ids []string) error {
+	list, err := r.items(section)
+	if err != nil {
+		return err
+	}
+
+	invalid := Invalid("ids", "必须包含该章节全部条目的ID且不能重复")
+	if len(ids) != list.len() {
+		return invalid
+	}
+	order := make([]int, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for n, id := range ids {
+		i, err := indexOf(list, id)
+		if err != nil || seen[i] {
+			return invalid
+		}
+		seen[i] = true
+		order[n] = i
+	}
+	list.reorder(order)
+	return nil
+}
+
+// AssignItemIDs 为缺少ID或ID重复的条目分配ID：优先沿用 previous 中同一条目的ID
+// （按合并简历时的规则判断是否为同一条目），否则生成新ID。previous 可以为空
+func AssignItemIDs(r, previous *Resume) {
+	if previous == nil {
+		previous = &Resume{}
+	}
+	assignIDs(r.Education, previous.Education, educationID, sameEducation)
+	assignIDs(r.Experience, previous.Experience, experienceID, sameExperience)
+	assignIDs(r.Projects, previous.Projects, projectID, sameProject)
+	skills := skillsWithIDs(r)
+	assignIDs(skills, skillsWithIDs(previous), skillID, func(a, b skillWithID) bool { return sameSkill(a.Name, b.Name) })
+	setSkillIDs(r, skills)
+	assignIDs(r.Certifications, previous.Certifications, certificationID, sameCertification)
+	assignIDs(r.Awards, previous.Awards, awardID, sameAward)
+	assignIDs(r.Languages, previous.Languages, languageID, sameLanguage)
+	assignIDs(r.Links, previous.Links, linkID, sameLink)
+	assignIDs(r.Publications, previous.Publications, publicationID, samePublication)
+
+	assignIDs(r.CustomSections, previous.CustomSections, customSectionID, sameCustomSection)
+	for i := range r.CustomSections {
+		section := &r.CustomSections[i]
+		var prev []CustomEntry
+		if j := slices.IndexFunc(previous.CustomSections, func(p CustomSection) bool { return p.ID == section.ID }); j >= 0 {
+			prev = previous.CustomSections[j].Entries
+		}
+		assignIDs(section.Entries, prev, customEntryID, sameCustomEntry)
+	}
+}
+
+// AssignLegacyIDs 为升级前保存的、没有ID的条目生成固定的ID（由简历ID、章节和位置决定），
+// 保证再次写入前多次读取得到相同的ID；写入时这些ID会被保存
+func AssignLegacyIDs(r *Resume) {
+	legacy := func(section string) func(i int) string {
+		return func(i int) string {
+			return uuid.NewSHA1(uuid.NameSpaceOID, []byte(r.ID+"/"+section+"/"+strconv.Itoa(i))).String()
+		}
+	}
+	fillIDs(r.Education, educationID, legacy(SectionEducation))
+	fillIDs(r.Experience, experienceID, legacy(SectionExperience))
+	fillIDs(r.Projects, projectID, legacy(SectionProjects))
+	alignSkillIDs(r, legacy(SectionSkills))
+	fillIDs(r.Certifications, certificationID, legacy(SectionCertifications))
+	fillIDs(r.Awards, awardID, legacy(SectionAwards))
+	fillIDs(r.Languages, languageID, legacy(SectionLanguages))
+	fillIDs(r.Links, linkID, legacy(SectionLinks))
+	fillIDs(r.Publications, publicationID, legacy(SectionPublications))
+	fillIDs(r.CustomSections, customSectionID, legacy(SectionCustom))
+	for i := range r.CustomSections {
+		fillIDs(r.CustomSections[i].Entries, customEntryID, legacy(CustomSectionPrefix+r.CustomSections[i].ID))
+	}
+}
+
+func educationID(e *Education) *string         { return &e.ID }
+func experienceID(e *Experience) *string       { return &e.ID }
+func projectID(p *Project) *string             { return &p.ID }
+func skillID(s *skillWithID) *string           { return &s.ID }
+func certificationID(c *Certification) *string { return &c.ID }
+func awardID(a *Award) *string                 { return &a.ID }
+func languageID(l *Language) *string           { return &l.ID }
+func linkID(l *Link) *string                   { return &l.ID }
+func publicationID(p *Publication) *string     { return &p.ID }
+func customSectionID(s *CustomSection) *string { return &s.ID }
+func customEntryID(e *CustomEntry) *string     { return &e.ID }
+
+// skillWithID 技能文本及其ID，用于按条目分配技能ID
+type skillWithID struct {
+	ID   string
+	Name string
+}
+
+// skillsWithIDs 技能及其ID；SkillIDs 与 Skills 条数不一致时无法对应，ID全部为空
+func skillsWithIDs(r *Resume) []skillWithID {
+	skills := make([]skillWithID, len(r.Skills))
+	for i, s := range r.Skills {
+		skills[i].Name = s
+		if len(r.SkillIDs) == len(r.Skills) {
+			skills[i].ID = r.SkillIDs[i]
+		}
+	}
+	return skills
+}
+
+func setSkillIDs(r *Resume, skills []skillWithID) {
+	r.SkillIDs = make([]string, len(skills))
+	for i, s := range skills {
+		r.SkillIDs[i] = s.ID
+	}
+}
+
+// alignSkillIDs 为缺少ID的技能用 generate 生成ID，保证 SkillIDs 与 Skills 一一对应
+func alignSkillIDs(r *Resume, generate func(i int) string) {
+	skills := skillsWithIDs(r)
+	fillIDs(skills, skillID, generate)
+	setSkillIDs(r, skills)
+}
+
+func assignIDs[T any](items, previous []T, id func(*T) *string, same func(a, b T) bool) {
+	used := make(map[string]bool, len(items))
+	for i := range items {
+		p := id(&items[i])
+		if used[*p] {
+			*p = ""
+		}
+		if *p != "" {
+			used[*p] = true
+		}
+	}
+
+	for i := range items {
+		p := id(&items[i])
+		if *p != "" {
+			continue
+		}
+		for j := range previous {
+			if prev := *id(&previous[j]); prev != "" && !used[prev] && same(previous[j], items[i]) {
+				*p = prev
+				break
+			}
+		}
+		if *p == "" {
+			*p = NewItemID()
+		}
+		used[*p] = true
+	}
+}
+
+func fillIDs[T any](items []T, id func(*T) *string, generate func(i int) string) {
+	for i := range items {
+		if p := id(&items[i]); *p == "" {
+			*p = generate(i)
+		}
+	}
+}
+
+// itemList 对单个章节的条目进行增删改和排序
+type itemList interface {
+	len() int
+	id(i int) string
+	insert(position int, item []byte) (string, error)
+	patch(i int, patch []byte) error
+	remove(i int)
+	reorder(order []int)
+}
+
+func (r *Resume) items(section string) (itemList, error) {
+	switch section {
+	case SectionEducation:
+		return &structItems[Education]{items: &r.Education, idOf: educationID}, nil
+	case SectionExperience:
+		return &structItems[Experience]{items: &r.Experience, idOf: experienceID}, nil
+	case SectionProjects:
+		return &structItems[Project]{items: &r.Projects, idOf: projectID}, nil
+	case SectionSkills:
+		alignSkillIDs(r, func(int) string { return NewItemID() })
+		return &skillItems{items: &r.Skills, ids: &r.SkillIDs}, nil
+	case SectionCertifications:
+		return &structItems[Certification]{items: &r.Certifications, idOf: certificationID}, nil
+	case SectionAwards:
+		return &structItems[Award]{items: &r.Awards, idOf: awardID}, nil
+	case SectionLanguages:
+		return &structItems[Language]{items: &r.Languages, idOf: languageID}, nil
+	case SectionLinks:
+		return &structItems[Link]{items: &r.Links, idOf: linkID}, nil
+	case SectionPublications:
+		return &structItems[Publication]{items: &r.Publications, idOf: publicationID}, nil
+	case SectionCustom:
+		return &structItems[CustomSection]{items: &r.CustomSections, idOf: customSectionID}, nil
+	}
+	if id, ok := strings.CutPrefix(section, CustomSectionPrefix); ok {
+		for i := range r.CustomSections {
+			if r.CustomSections[i].ID == id {
+				return &structItems[CustomEntry]{items: &r.CustomSections[i].Entries, idOf: customEntryID}, nil
+			}
+		}
+		return nil, ErrItemNotFound
+	}
+	return nil, Invalid("section", "不支持的章节: "+section+
+		"（可选 education、experience、projects、skills、certifications、awards、languages、links、publications、custom_sections 或 custom:<自定义章节ID>）")
+}
+
+func indexOf(list itemList, id string) (int, error) {
+	for i := 0; i < list.len(); i++ {
+		if list.id(i) == id {
+			return i, nil
+		}
+	}
+	return 0, ErrItemNotFound
+}
+
+// structItems 教育、工作、项目、证书等带ID的条目
+type structItems[T any] struct {
+	items *[]T
+	idOf  func(*T) *string
+}
+
+func (l *structItems[T]) len() int { return len(*l.items) }
+
+func (l *structItems[T]) id(i int) string { return *l.idOf(&(*l.items)[i]) }
+
+func (l *structItems[T]) insert(position int, item []byte) (string, error) {
+	var v T
+	if err := decodeStrict(item, &v); err != nil {
+		return "", err
+	}
+	id := NewItemID()
+	*l.idOf(&v) = id
+	*l.items = slices.Insert(*l.items, position, v)
+	return id, nil
+}
+
+func (l *structItems[T]) patch(i int, patch []byte) error {
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return Invalid("", "补丁不是有效的 JSON: "+err.Error())
+	}
+	if _, ok := p.(map[string]any); !ok {
+		return Invalid("", "补丁必须是 JSON 对象")
+	}
+
+	item := &(*l.items)[i]
+	id := *l.idOf(item)
+	var patched T
+	if err := mergeInto(item, p, &patched); err != nil {
+		return err
+	}
+	// 条目ID不可修改
+	*l.idOf(&patched) = id
+	*item = patched
+	return nil
+}
+
+func (l *structItems[T]) remove(i int) { *l.items = slices.Delete(*l.items, i, i+1) }
+
+func (l *structItems[T]) reorder(order []int) { *l.items = reorder(*l.items, order) }
+
+// skillItems 技能条目，ID保存在与技能按下标对应的 ids 中
+type skillItems struct {
+	items *[]string
+	ids   *[]string
+}
+
+func (l *skillItems) len() int { return len(*l.items) }
+
+func (l *skillItems) id(i int) string { return (*l.ids)[i] }
+
+func (l *skillItems) insert(position int, item []byte) (string, error) {
+	var s string
+	if err := decodeStrict(item, &s); err != nil {
+		return "", err
+	}
+	id := NewItemID()
+	*l.items = slices.Insert(*l.items, position, s)
+	*l.ids = slices.Insert(*l.ids, position, id)
+	return id, nil
+}
+
+func (l *skillItems) patch(i int, patch []byte) error {
+	var s string
+	if err := decodeStrict(patch, &s); err != nil {
+		return err
+	}
+	(*l.items)[i] = s
+	return nil
+}
+
+func (l *skillItems) remove(i int) {
+	*l.items = slices.Delete(*l.items, i, i+1)
+	*l.ids = slices.Delete(*l.ids, i, i+1)
+}
+
+func (l *skillItems) reorder(order []int) {
+	*l.items = reorder(*l.items, order)
+	*l.ids = reorder(*l.ids, order)
+}
+
+func reorder[T any](items []T, order []int) []T {
+	out := make([]T, len(order))
+	for n, i := range order {
+		out[n] = items[i]
+	}
+	return out
+}
+
+// decodeStrict 解码条目，不允许未知字段
+func decodeStrict(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return Invalid("", "条目内容无效: "+err.Error())
+	}
+	return nil
+}
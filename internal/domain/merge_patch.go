@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// protectedFields 不能通过 JSON Merge Patch 修改的字段：ID、时间和版本由服务端维护，名称和默认标记有单独的接口
+var protectedFields = []string{"id", "user_id", "name", "is_default", "created_at", "updated_at", "version", "skill_ids"}
+
+// ApplyMergePatch 按 RFC 7396（JSON Merge Patch）修改简历内容：
+// 对象按字段递归合并，值为 null 的字段被删除，数组整体替换。
+// 补丁不是 JSON 对象、包含未知字段或受保护字段时返回 *ValidationError
+func (r *Resume) ApplyMergePatch(patch []byte) error {
+	p, err := decodeJSON(patch)
+	if err != nil {
+		return Invalid("", "补丁不是有效的 JSON: "+err.Error())
+	}
+	obj, ok := p.(map[string]any)
+	if !ok {
+		return Invalid("", "补丁必须是 JSON 对象")
+	}
+	for _, name := range protectedFields {
+		if _, ok := obj[name]; ok {
+			return Invalid(name, fmt.Sprintf("%s 不能通过 PATCH 修改", name))
+		}
+	}
+
+	var patched Resume
+	if err := mergeInto(r, obj, &patched); err != nil {
+		return err
+	}
+	r.BasicInfo = patched.BasicInfo
+	r.Education = patched.Education
+	r.Experience = patched.Experience
+	r.Projects = patched.Projects
+	if _, ok := obj["skills"]; ok {
+		// 技能整体替换，保存时按技能文本沿用已有技能的ID
+		r.Skills, r.SkillIDs = patched.Skills, nil
+	}
+	r.Summary = patched.Summary
+	r.Certifications = patched.Certifications
+	r.Awards = patched.Awards
+	r.Languages = patched.Languages
+	r.Links = patched.Links
+	r.Publications = patched.Publications
+	r.CustomSections = patched.CustomSections
+	return nil
+}
+
+// mergeInto 将 patch 合并到 src 的 JSON 表示上，再严格解码到 dst（不允许未知字段）
+func mergeInto(src any, patch any, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	doc, err := decodeJSON(data)
+	if err != nil {
+		return err
+	}
+
+	merged, err := json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return err
+	}
+	dec := json.NewDecoder(bytes.NewReader(merged))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(dst); err != nil {
+		return Invalid("", "补丁内容无效: "+err.Error())
+	}
+	return nil
+}
+
+// mergePatch RFC 7396 的 MergePatch 算法
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = map[string]any{}
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+		} else {
+			t[k] = mergePatch(t[k], v)
+		}
+	}
+	return t
+}
+
+// decodeJSON 解码任意 JSON 值，数字保持原样
+func decodeJSON(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, fmt.Errorf("JSON 之后存在多余内容")
+	}
+	return v, nil
+}
@@ -0,0 +1,181 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestApplyMergePatch(t *testing.T) {
+	base := func() *Resume {
+		return &Resume{
+			ID:        "r1",
+			Name:      "默认简历",
+			Version:   2,
+			BasicInfo: []BasicInfo{{Name: "张三", Email: "zs@example.com"}},
+			Summary:   "五年后端经验",
+			Education: []Education{{ID: "e1", School: "北京大学", Degree: "本科"}},
+			Skills:    []string{"Go", "MySQL"},
+			SkillIDs:  []string{"s1", "s2"},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		patch string
+		check func(t *testing.T, r *Resume)
+	}{
+		{"空补丁", `{}`, func(t *testing.T, r *Resume) {
+			if !reflect.DeepEqual(r, base()) {
+				t.Errorf("r = %+v", r)
+			}
+		}},
+		{"修改文本字段", `{"summary":"资深后端工程师"}`, func(t *testing.T, r *Resume) {
+			if r.Summary != "资深后端工程师" || len(r.Education) != 1 || r.SkillIDs == nil {
+				t.Errorf("r = %+v", r)
+			}
+		}},
+		{"null 删除字段", `{"summary":null,"education":null}`, func(t *testing.T, r *Resume) {
+			if r.Summary != "" || r.Education != nil || len(r.Skills) != 2 {
+				t.Errorf("r = %+v", r)
+			}
+		}},
+		{"数组整体替换", `{"basic_info":[{"phone":"13800000000"}]}`, func(t *testing.T, r *Resume) {
+			if b := r.BasicInfo; len(b) != 1 || b[0].Phone != "13800000000" || b[0].Name != "" {
+				t.Errorf("BasicInfo = %+v", b)
+			}
+		}},
+		{"替换技能时清空技能ID", `{"skills":["Go","Redis"]}`, func(t *testing.T, r *Resume) {
+			if !reflect.DeepEqual(r.Skills, []string{"Go", "Redis"}) || r.SkillIDs != nil {
+				t.Errorf("Skills = %v, SkillIDs = %v", r.Skills, r.SkillIDs)
+			}
+		}},
+		{"日期按 PartialDate 解析", `{"education":[{"school":"清华大学","start_date":"2015.9"}]}`, func(t *testing.T, r *Resume) {
+			if e := r.Education[0]; e.School != "清华大学" || e.StartDate != (PartialDate{Year: 2015, Month: 9}) {
+				t.Errorf("Education = %+v", r.Education)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := base()
+			if err := r.ApplyMergePatch([]byte(tt.patch)); err != nil {
+				t.Fatalf("ApplyMergePatch(%s): %v", tt.patch, err)
+			}
+			if r.ID != "r1" || r.Name != "默认简历" || r.Version != 2 {
+				t.Errorf("补丁修改了受保护的字段: %+v", r)
+			}
+			tt.check(t, r)
+		})
+	}
+}
+
+func TestApplyMergePatchInvalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		field string
+	}{
+		{"不是 JSON", `{"summary":`, ""},
+		{"多余内容", `{} {}`, ""},
+		{"不是对象", `["summary"]`, ""},
+		{"受保护的字段", `{"version":3}`, "version"},
+		{"名称有单独的接口", `{"name":"新名称"}`, "name"},
+		{"技能ID由服务端维护", `{"skill_ids":["a"]}`, "skill_ids"},
+		{"未知字段", `{"hobbies":["跑步"]}`, ""},
+		{"条目中的未知字段", `{"education":[{"school":"a","gpa":4}]}`, ""},
+		{"类型错误", `{"skills":"Go"}`, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Resume{Summary: "简介"}
+			err := r.ApplyMergePatch([]byte(tt.patch))
+			var invalid *ValidationError
+			if !errors.As(err, &invalid) || invalid.Fields[0].Field != tt.field {
+				t.Fatalf("ApplyMergePatch(%s) = %v，期望字段 %q 的 *ValidationError", tt.patch, err, tt.field)
+			}
+			if r.Summary != "简介" {
+				t.Errorf("失败的补丁修改了简历: %+v", r)
+			}
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	// RFC 7396 附录 A 中的示例
+	tests := []struct {
+		target, patch, want string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, tt := range tests {
+		target, err := decodeJSON([]byte(tt.target))
+		if err != nil {
+			t.Fatalf("decodeJSON(%s): %v", tt.target, err)
+		}
+		patch, err := decodeJSON([]byte(tt.patch))
+		if err != nil {
+			t.Fatalf("decodeJSON(%s): %v", tt.patch, err)
+		}
+		got, _ := json.Marshal(mergePatch(target, patch))
+		if string(got) != tt.want {
+			t.Errorf("mergePatch(%s, %s) = %s，期望 %s", tt.target, tt.patch, got, tt.want)
+		}
+	}
+}
+
+func TestSkillItemIDs(t *testing.T) {
+	r := &Resume{ID: "r1", Skills: []string{"Go", "MySQL", "Redis"}}
+	AssignLegacyIDs(r)
+	ids := slices.Clone(r.SkillIDs)
+	if len(ids) != 3 {
+		t.Fatalf("SkillIDs = %v", r.SkillIDs)
+	}
+
+	// 删除前面的技能后，后面技能的ID不变
+	if err := r.DeleteItem(SectionSkills, ids[0]); err != nil {
+		t.Fatalf("DeleteItem: %v", err)
+	}
+	if err := r.PatchItem(SectionSkills, ids[2], []byte(`"Redis Cluster"`)); err != nil {
+		t.Fatalf("PatchItem: %v", err)
+	}
+	id, err := r.AddItem(SectionSkills, []byte(`"Kafka"`), 0)
+	if err != nil {
+		t.Fatalf("AddItem: %v", err)
+	}
+	if err := r.ReorderItems(SectionSkills, []string{ids[2], id, ids[1]}); err != nil {
+		t.Fatalf("ReorderItems: %v", err)
+	}
+	if want := []string{"Redis Cluster", "Kafka", "MySQL"}; !reflect.DeepEqual(r.Skills, want) {
+		t.Errorf("Skills = %v，期望 %v", r.Skills, want)
+	}
+	if want := []string{ids[2], id, ids[1]}; !reflect.DeepEqual(r.SkillIDs, want) {
+		t.Errorf("SkillIDs = %v，期望 %v", r.SkillIDs, want)
+	}
+	if err := r.DeleteItem(SectionSkills, ids[0]); !errors.Is(err, ErrItemNotFound) {
+		t.Errorf("删除已删除的技能 = %v，期望 ErrItemNotFound", err)
+	}
+
+	// 整体保存时按内容沿用ID
+	next := &Resume{Skills: []string{"mysql", "Rust"}}
+	AssignItemIDs(next, r)
+	if next.SkillIDs[0] != ids[1] || slices.Contains(r.SkillIDs, next.SkillIDs[1]) {
+		t.Errorf("AssignItemIDs: SkillIDs = %v，原ID %v", next.SkillIDs, r.SkillIDs)
+	}
+}
@@ -0,0 +1,224 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OngoingText “至今”的序列化文本
+const OngoingText = "至今"
+
+// PartialDate 简历中的日期，精度可以只到年或月，也可以是“至今”。
+// JSON 中为字符串：2019、2019-09、2019-09-01 或 至今；
+// 无法识别的历史数据原样保留，读写不丢失，但不参与排序和时长计算
+type PartialDate struct {
+	Year    int  // 0 表示未填写
+	Month   int  // 0 表示只精确到年
+	Day     int  // 0 表示只精确到月
+	Ongoing bool // 至今（在读、在职）
+
+	raw string // 无法识别的原始文本
+}
+
+// ParseDate 解析日期，支持常见的中英文写法：
+// 2019、2019.09、2019/9/1、2019-09-01、2019年9月、2019年9月1日、09/2019、Sep 2019、September 1, 2019，
+// 以及表示至今的 至今、今、现在、在读、在职、present、now、current 等
+func ParseDate(s string) (PartialDate, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return PartialDate{}, nil
+	}
+	if ongoingWords[strings.ToLower(s)] {
+		return PartialDate{Ongoing: true}, nil
+	}
+
+	var year, month, day string
+	if m := ymdPattern.FindStringSubmatch(s); m != nil {
+		year, month, day = m[1], m[2], m[3]
+	} else if m := myPattern.FindStringSubmatch(s); m != nil {
+		year, month = m[2], m[1]
+	} else if m := monthNamePattern.FindStringSubmatch(s); m != nil {
+		year, day = m[3], m[2]
+		if month = monthNumber(m[1]); month == "" {
+			return PartialDate{}, fmt.Errorf("无法识别的日期: %s", s)
+		}
+	} else if m := yearMonthNamePattern.FindStringSubmatch(s); m != nil {
+		year = m[1]
+		if month = monthNumber(m[2]); month == "" {
+			return PartialDate{}, fmt.Errorf("无法识别的日期: %s", s)
+		}
+	} else {
+		return PartialDate{}, fmt.Errorf("无法识别的日期: %s", s)
+	}
+
+	d := PartialDate{}
+	d.Year, _ = strconv.Atoi(year)
+	if month != "" {
+		d.Month, _ = strconv.Atoi(month)
+	}
+	if day != "" {
+		d.Day, _ = strconv.Atoi(day)
+	}
+	// 写出的月、日必须有效：2019-00 不是只精确到年，2019-09-00 也不是只精确到月
+	if (month != "" && (d.Month < 1 || d.Month > 12)) ||
+		(day != "" && (d.Day < 1 || time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC).Day() != d.Day)) {
+		return PartialDate{}, fmt.Errorf("无效的日期: %s", s)
+	}
+	return d, nil
+}
+
+var (
+	// 2019、2019.09、2019/9/1、2019年9月1日
+	ymdPattern = regexp.MustCompile(`^(\d{4})\s*(?:[-./年]\s*(\d{1,2})\s*(?:[-./月]\s*(\d{1,2})\s*日?|月)?)?\s*年?$`)
+	// 09/2019、9.2019
+	myPattern = regexp.MustCompile(`^(\d{1,2})\s*[-./]\s*(\d{4})$`)
+	// Sep 2019、September 1, 2019
+	monthNamePattern = regexp.MustCompile(`^([A-Za-z]+)\.?\s*(?:(\d{1,2})\s*,?\s*)?,?\s*(\d{4})$`)
+	// 2019 Sep
+	yearMonthNamePattern = regexp.MustCompile(`^(\d{4})\s*,?\s*([A-Za-z]+)\.?$`)
+)
+
+// ongoingWords 表示“至今”的写法（小写）
+var ongoingWords = map[string]bool{
+	"至今": true, "今": true, "现在": true, "目前": true, "在读": true, "在职": true,
+	"present": true, "now": true, "current": true, "ongoing": true, "till now": true, "to date": true, "to present": true,
+}
+
+// monthNumber 英文月份名（全称或缩写）对应的月份，无法识别时返回空
+func monthNumber(name string) string {
+	name = strings.ToLower(name)
+	if len(name) < 3 {
+		return ""
+	}
+	for i, m := range []string{"january", "february", "march", "april", "may", "june",
+		"july", "august", "september", "october", "november", "december"} {
+		if strings.HasPrefix(m, name) || (name == "sept" && i == 8) {
+			return strconv.Itoa(i + 1)
+		}
+	}
+	return ""
+}
+
+// IsZero 未填写
+func (d PartialDate) IsZero() bool {
+	return d == PartialDate{}
+}
+
+// Valid 已填写且可以识别
+func (d PartialDate) Valid() bool {
+	return d.raw == "" && !d.IsZero()
+}
+
+// String 规范化的文本，无法识别的日期返回原始文本
+func (d PartialDate) String() string {
+	switch {
+	case d.raw != "":
+		return d.raw
+	case d.Ongoing:
+		return OngoingText
+	case d.Year == 0:
+		return ""
+	case d.Month == 0:
+		return fmt.Sprintf("%04d", d.Year)
+	case d.Day == 0:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	}
+	return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+}
+
+// Compare 按时间先后比较，返回 -1、0、1。至今最晚，缺少的月、日按最早计算；
+// 未填写或无法识别的日期视为最早
+func (d PartialDate) Compare(other PartialDate) int {
+	a, b := d.sortKey(), other.sortKey()
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func (d PartialDate) sortKey() [4]int {
+	if !d.Valid() {
+		return [4]int{}
+	}
+	if d.Ongoing {
+		return [4]int{1}
+	}
+	return [4]int{0, d.Year, d.Month, d.Day}
+}
+
+// Before d 是否确定早于 other，只比较双方都有的精度（2020 与 2020-09 不分先后）
+func (d PartialDate) Before(other PartialDate) bool {
+	switch {
+	case !d.Valid() || !other.Valid() || d.Ongoing:
+		return false
+	case other.Ongoing:
+		return true
+	case d.Year != other.Year:
+		return d.Year < other.Year
+	case d.Month == 0 || other.Month == 0:
+		return false
+	case d.Month != other.Month:
+		return d.Month < other.Month
+	case d.Day == 0 || other.Day == 0:
+		return false
+	}
+	return d.Day < other.Day
+}
+
+// Months 从 start 到 end 的整月数，end 为至今时计算到 now；日期缺失或无法识别时 ok 为 false。
+// 只精确到年的日期按 1 月计算
+func Months(start, end PartialDate, now time.Time) (months int, ok bool) {
+	if !start.Valid() || start.Ongoing || !end.Valid() {
+		return 0, false
+	}
+	if end.Ongoing {
+		end = PartialDate{Year: now.Year(), Month: int(now.Month())}
+	}
+	months = (end.Year-start.Year)*12 + max(end.Month, 1) - max(start.Month, 1)
+	if months < 0 {
+		return 0, false
+	}
+	return months, true
+}
+
+func (d PartialDate) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON 接受字符串、数字（年份）和 null；无法识别的字符串原样保留
+func (d *PartialDate) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		*d = PartialDate{}
+		return nil
+	}
+
+	var s string
+	if len(data) > 0 && data[0] != '"' {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("日期应为字符串: %s", data)
+		}
+		s = n.String()
+	} else if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		*d = PartialDate{raw: strings.TrimSpace(s)}
+		return nil
+	}
+	*d = parsed
+	return nil
+}
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want PartialDate
+	}{
+		{"", PartialDate{}},
+		{"2019", PartialDate{Year: 2019}},
+		{"2019年", PartialDate{Year: 2019}},
+		{"2019-09", PartialDate{Year: 2019, Month: 9}},
+		{"2019.9", PartialDate{Year: 2019, Month: 9}},
+		{"2019/09/01", PartialDate{Year: 2019, Month: 9, Day: 1}},
+		{"2019年9月", PartialDate{Year: 2019, Month: 9}},
+		{"2019年9月1日", PartialDate{Year: 2019, Month: 9, Day: 1}},
+		{"09/2019", PartialDate{Year: 2019, Month: 9}},
+		{"Sep 2019", PartialDate{Year: 2019, Month: 9}},
+		{"Sept. 2019", PartialDate{Year: 2019, Month: 9}},
+		{"September 1, 2019", PartialDate{Year: 2019, Month: 9, Day: 1}},
+		{"2019 Sep", PartialDate{Year: 2019, Month: 9}},
+		{" 2020-02-29 ", PartialDate{Year: 2020, Month: 2, Day: 29}},
+		{"至今", PartialDate{Ongoing: true}},
+		{"在读", PartialDate{Ongoing: true}},
+		{"Present", PartialDate{Ongoing: true}},
+	}
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDate(%q) = %+v, %v，期望 %+v", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	for _, in := range []string{
+		"2019-00", "2019.00", "00/2019", "2019-13", "2019-09-00", "2019-02-30", "2019-02-29",
+		"2019.09-2020.06", "Foo 2019", "去年", "19-09",
+	} {
+		if got, err := ParseDate(in); err == nil {
+			t.Errorf("ParseDate(%q) = %+v，期望返回错误", in, got)
+		}
+	}
+}
+
+func TestPartialDateJSON(t *testing.T) {
+	tests := []struct {
+		in    string
+		want  string // 重新序列化的结果
+		valid bool
+	}{
+		{`"2019.9"`, `"2019-09"`, true},
+		{`"2019年9月1日"`, `"2019-09-01"`, true},
+		{`2019`, `"2019"`, true},
+		{`"present"`, `"至今"`, true},
+		{`null`, `""`, false},
+		{`""`, `""`, false},
+		// 无法识别的历史数据原样保留
+		{`"2019.09-2020.06"`, `"2019.09-2020.06"`, false},
+		{`"2019-00"`, `"2019-00"`, false},
+	}
+	for _, tt := range tests {
+		var d PartialDate
+		if err := json.Unmarshal([]byte(tt.in), &d); err != nil {
+			t.Errorf("Unmarshal(%s): %v", tt.in, err)
+			continue
+		}
+		got, err := json.Marshal(d)
+		if err != nil || string(got) != tt.want || d.Valid() != tt.valid {
+			t.Errorf("%s 往返后 = %s, %v，Valid = %v，期望 %s, %v", tt.in, got, err, d.Valid(), tt.want, tt.valid)
+		}
+	}
+
+	var d PartialDate
+	if err := json.Unmarshal([]byte(`{"year":2019}`), &d); err == nil {
+		t.Error("对象不应解码为日期")
+	}
+}
+
+func TestPartialDateCompare(t *testing.T) {
+	date := func(s string) PartialDate {
+		d, err := ParseDate(s)
+		if err != nil {
+			t.Fatalf("ParseDate(%q): %v", s, err)
+		}
+		return d
+	}
+	tests := []struct {
+		a, b    string
+		compare int
+		before  bool
+	}{
+		{"2019", "2020", -1, true},
+		{"2020-09", "2020-03", 1, false},
+		{"2020", "2020-09", -1, false}, // 只比较双方都有的精度
+		{"2020-09-01", "2020-09-15", -1, true},
+		{"2020-09", "至今", -1, true},
+		{"至今", "至今", 0, false},
+		{"", "2019", -1, false},
+	}
+	for _, tt := range tests {
+		a, b := date(tt.a), date(tt.b)
+		if got := a.Compare(b); got != tt.compare {
+			t.Errorf("Compare(%q, %q) = %d，期望 %d", tt.a, tt.b, got, tt.compare)
+		}
+		if got := a.Before(b); got != tt.before {
+			t.Errorf("Before(%q, %q) = %v，期望 %v", tt.a, tt.b, got, tt.before)
+		}
+	}
+}
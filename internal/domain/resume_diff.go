@@ -0,0 +1,220 @@
+package domain
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ResumeDiff 两个简历版本之间按章节划分的结构化差异
+type ResumeDiff struct {
+	Changed  bool          `json:"changed"`
+	Sections []SectionDiff `json:"sections"` // 只包含有变化的章节
+}
+
+// SectionDiff 单个章节的差异
+type SectionDiff struct {
+	Section  string       `json:"section"`
+	Added    []any        `json:"added,omitempty"`
+	Removed  []any        `json:"removed,omitempty"`
+	Modified []ItemChange `json:"modified,omitempty"`
+}
+
+// ItemChange 同一条目（按章节的识别键匹配）在两个版本间的变化
+type ItemChange struct {
+	Key    string   `json:"key"`
+	Fields []string `json:"fields"` // 发生变化的字段（JSON字段名）
+	Before any      `json:"before"`
+	After  any      `json:"after"`
+}
+
+// 各章节的识别键：同一条目在不同版本中即使内容被修改也保持相同的键
+
+func basicInfoKey(BasicInfo) string { return "basic_info" }
+
+func educationKey(e Education) string {
+	return normalizeKey(e.School) + "|" + normalizeKey(e.Major)
+}
+
+func experienceKey(e Experience) string {
+	return normalizeKey(e.Company) + "|" + normalizeKey(e.Position)
+}
+
+func projectKey(p Project) string {
+	if p.URL != "" {
+		return urlKey(p.URL)
+	}
+	return normalizeKey(p.Name)
+}
+
+func skillKey(s string) string { return normalizeKey(s) }
+
+func summaryKey(string) string { return "summary" }
+
+func certificationKey(c Certification) string {
+	return normalizeKey(c.Name) + "|" + normalizeKey(c.Issuer)
+}
+
+func awardKey(a Award) string {
+	return normalizeKey(a.Title) + "|" + normalizeKey(a.Issuer)
+}
+
+func languageKey(l Language) string { return normalizeKey(l.Language) }
+
+func linkKey(l Link) string { return urlKey(l.URL) }
+
+func publicationKey(p Publication) string {
+	if p.URL != "" {
+		return urlKey(p.URL)
+	}
+	return normalizeKey(p.Title)
+}
+
+func customSectionKey(s CustomSection) string { return normalizeKey(s.Title) }
+
+func customEntryKey(e CustomEntry) string {
+	return normalizeKey(e.Title) + "|" + normalizeKey(e.Subtitle)
+}
+
+// urlKey 规范化URL，忽略大小写和末尾的斜杠
+func urlKey(u string) string {
+	return strings.TrimSuffix(normalizeKey(u), "/")
+}
+
+// normalizeKey 统一大小写并去除多余空白
+func normalizeKey(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// DiffResumes 计算 from 到 to 的结构化差异
+func DiffResumes(from, to *Resume) ResumeDiff {
+	if from == nil {
+		from = &Resume{}
+	}
+	if to == nil {
+		to = &Resume{}
+	}
+
+	candidates := []SectionDiff{
+		diffSection("basic_info", from.BasicInfo, to.BasicInfo, basicInfoKey),
+		diffSection("summary", text(from.Summary), text(to.Summary), summaryKey),
+		diffSection("education", from.Education, to.Education, educationKey),
+		diffSection("experience", from.Experience, to.Experience, experienceKey),
+		diffSection("projects", from.Projects, to.Projects, projectKey),
+		diffSection("skills", from.Skills, to.Skills, skillKey),
+		diffSection("certifications", from.Certifications, to.Certifications, certificationKey),
+		diffSection("awards", from.Awards, to.Awards, awardKey),
+		diffSection("languages", from.Languages, to.Languages, languageKey),
+		diffSection("links", from.Links, to.Links, linkKey),
+		diffSection("publications", from.Publications, to.Publications, publicationKey),
+		diffSection(SectionCustom, from.CustomSections, to.CustomSections, customSectionKey),
+	}
+
+	diff := ResumeDiff{Sections: []SectionDiff{}}
+	for _, s := range candidates {
+		if len(s.Added) > 0 || len(s.Removed) > 0 || len(s.Modified) > 0 {
+			diff.Sections = append(diff.Sections, s)
+		}
+	}
+	diff.Changed = len(diff.Sections) > 0
+	return diff
+}
+
+// text 将单个文本字段视为章节：为空时没有条目
+func text(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// diffSection 按识别键匹配两个版本的条目，得出新增、删除和修改
+func diffSection[T any](name string, before, after []T, key func(T) string) SectionDiff {
+	section := SectionDiff{Section: name}
+
+	beforeKeys := uniqueKeys(before, key)
+	afterKeys := uniqueKeys(after, key)
+
+	afterIndex := make(map[string]int, len(after))
+	for i, k := range afterKeys {
+		afterIndex[k] = i
+	}
+	beforeIndex := make(map[string]int, len(before))
+	for i, k := range beforeKeys {
+		beforeIndex[k] = i
+	}
+
+	for i, k := range beforeKeys {
+		j, ok := afterIndex[k]
+		if !ok {
+			section.Removed = append(section.Removed, before[i])
+			continue
+		}
+		if fields := changedFields(before[i], after[j]); len(fields) > 0 {
+			section.Modified = append(section.Modified, ItemChange{
+				Key:    k,
+				Fields: fields,
+				Before: before[i],
+				After:  after[j],
+			})
+		}
+	}
+	for j, k := range afterKeys {
+		if _, ok := beforeIndex[k]; !ok {
+			section.Added = append(section.Added, after[j])
+		}
+	}
+	return section
+}
+
+// uniqueKeys 计算每个条目的识别键，同一版本内重复的键追加序号区分
+func uniqueKeys[T any](items []T, key func(T) string) []string {
+	keys := make([]string, len(items))
+	seen := make(map[string]int, len(items))
+	for i, item := range items {
+		k := key(item)
+		seen[k]++
+		if n := seen[k]; n > 1 {
+			k += "#" + strconv.Itoa(n)
+		}
+		keys[i] = k
+	}
+	return keys
+}
+
+// changedFields 返回两个条目之间发生变化的字段名；非结构体（如技能字符串）整体比较
+func changedFields(a, b any) []string {
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Kind() != reflect.Struct {
+		if reflect.DeepEqual(a, b) {
+			return nil
+		}
+		return []string{"value"}
+	}
+
+	var fields []string
+	t := va.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if isItemID(t.Field(i)) {
+			continue
+		}
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			fields = append(fields, jsonFieldName(t.Field(i)))
+		}
+	}
+	return fields
+}
+
+// isItemID 条目ID只用于定位条目，不算作内容变化
+func isItemID(f reflect.StructField) bool {
+	return jsonFieldName(f) == "id"
+}
+
+// jsonFieldName 取结构体字段的 JSON 名称
+func jsonFieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffResumes(t *testing.T) {
+	base := func() *Resume {
+		return &Resume{
+			BasicInfo: []BasicInfo{{Name: "张三", Email: "zhangsan@example.com"}},
+			Education: []Education{{ID: "e1", School: "北京大学", Major: "计算机科学", Degree: "本科"}},
+			Experience: []Experience{
+				{ID: "x1", Company: "字节跳动", Position: "后端工程师", Achievements: []string{"重构网关"}},
+			},
+			Projects: []Project{{ID: "p1", Name: "ResumeBuilder", URL: "https://github.com/u/resume"}},
+			Skills:   []string{"Go", "MySQL"},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		change func(r *Resume)
+		want   []string // 见 summarize
+	}{
+		{"相同", func(*Resume) {}, nil},
+		{"条目ID不算内容变化", func(r *Resume) { r.Education[0].ID = "other" }, nil},
+		{"识别键忽略大小写和空白", func(r *Resume) { r.Skills[0] = " go " }, []string{"skills ~go(value)"}},
+		{
+			"修改字段",
+			func(r *Resume) { r.Education[0].Degree = "硕士"; r.BasicInfo[0].Email = "zs@example.com" },
+			[]string{"basic_info ~basic_info(email)", "education ~北京大学|计算机科学(degree)"},
+		},
+		{
+			"修改识别键视为删除后新增",
+			func(r *Resume) { r.Experience[0].Position = "架构师" },
+			[]string{"experience +1 -1"},
+		},
+		{
+			"项目按URL识别，改名算作修改",
+			func(r *Resume) {
+				r.Projects[0].Name = "简历生成器"
+				r.Projects[0].URL = "https://GitHub.com/u/resume/"
+			},
+			[]string{"projects ~https://github.com/u/resume(name,url)"},
+		},
+		{
+			"新增和删除",
+			func(r *Resume) { r.Skills = []string{"Go", "Redis"}; r.Summary = "五年后端经验" },
+			[]string{"summary +1", "skills +1 -1"},
+		},
+		{
+			"同一版本中重复的条目",
+			func(r *Resume) { r.Skills = append(r.Skills, "Go") },
+			[]string{"skills +1"},
+		},
+		{
+			"列表字段",
+			func(r *Resume) { r.Experience[0].Achievements = append(r.Experience[0].Achievements, "降低延迟") },
+			[]string{"experience ~字节跳动|后端工程师(achievements)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			to := base()
+			tt.change(to)
+			diff := DiffResumes(base(), to)
+			if got := summarize(diff); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DiffResumes = %q，期望 %q", got, tt.want)
+			}
+			if diff.Changed != (len(tt.want) > 0) {
+				t.Errorf("Changed = %v", diff.Changed)
+			}
+		})
+	}
+}
+
+func TestDiffResumesNil(t *testing.T) {
+	diff := DiffResumes(nil, &Resume{Skills: []string{"Go"}})
+	if got := summarize(diff); !reflect.DeepEqual(got, []string{"skills +1"}) {
+		t.Errorf("DiffResumes(nil, r) = %q", got)
+	}
+	if diff := DiffResumes(nil, nil); diff.Changed || diff.Sections == nil {
+		t.Errorf("DiffResumes(nil, nil) = %+v", diff)
+	}
+}
+
+// summarize 将差异概括为每个章节一行，如 "skills +1 -1" 或 "education ~键(字段)"
+func summarize(diff ResumeDiff) []string {
+	var lines []string
+	for _, s := range diff.Sections {
+		line := s.Section
+		if len(s.Added) > 0 {
+			line += fmt.Sprintf(" +%d", len(s.Added))
+		}
+		if len(s.Removed) > 0 {
+			line += fmt.Sprintf(" -%d", len(s.Removed))
+		}
+		for _, m := range s.Modified {
+			line += fmt.Sprintf(" ~%s(%s)", m.Key, strings.Join(m.Fields, ","))
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
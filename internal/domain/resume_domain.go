@@ -1,12 +1,62 @@
 package domain
 
+import (
+	"encoding/json"
+	"slices"
+	"time"
+)
+
+// DefaultResumeName 未指定名称时简历使用的名称
+const DefaultResumeName = "默认简历"
+
 type Resume struct {
+	ID         string       `json:"id"`
 	UserID     string       `json:"user_id"`
+	Name       string       `json:"name"`
+	IsDefault  bool         `json:"is_default"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+	Version    int64        `json:"version"` // 每次修改加一，接口中作为 ETag 返回
 	BasicInfo  []BasicInfo  `json:"basic_info"`
+	Summary    string       `json:"summary"` // 个人简介
 	Education  []Education  `json:"education"`
 	Experience []Experience `json:"experience"`
 	Projects   []Project    `json:"projects"`
 	Skills     []string     `json:"skills"`
+	SkillIDs   []string     `json:"skill_ids"` // 技能条目的ID，与 Skills 按下标对应
+
+	Certifications []Certification `json:"certifications"`
+	Awards         []Award         `json:"awards"`
+	Languages      []Language      `json:"languages"`
+	Links          []Link          `json:"links"` // 个人主页、GitHub、博客等
+	Publications   []Publication   `json:"publications"`
+
+	CustomSections []CustomSection `json:"custom_sections"` // 用户自定义章节，按展示顺序排列
+}
+
+// Clone 深拷贝简历，修改副本不影响原简历
+func (r *Resume) Clone() *Resume {
+	data, err := json.Marshal(r)
+	if err != nil {
+		panic(err) // 简历只包含可序列化的字段
+	}
+	var c Resume
+	if err := json.Unmarshal(data, &c); err != nil {
+		panic(err)
+	}
+	return &c
+}
+
+// ResumeSummary 简历列表中展示的概要信息
+type ResumeSummary struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	IsDefault bool       `json:"is_default"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	Version   int64      `json:"version"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // 移入回收站的时间，仅回收站列表中有值
 }
 
 type BasicInfo struct {
@@ -18,23 +68,26 @@ type BasicInfo struct {
 }
 
 type Education struct {
-	School    string `json:"school"`
-	Major     string `json:"major"`
-	StartDate string `json:"start_date"`
-	EndDate   string `json:"end_date"`
-	Degree    string `json:"degree"`
+	ID        string      `json:"id"` // 条目ID，由服务端生成，用于按条目编辑
+	School    string      `json:"school"`
+	Major     string      `json:"major"`
+	StartDate PartialDate `json:"start_date"`
+	EndDate   PartialDate `json:"end_date"`
+	Degree    string      `json:"degree"`
 }
 
 type Experience struct {
-	Company      string   `json:"company"`
-	Position     string   `json:"position"`
-	StartDate    string   `json:"start_date"`
-	EndDate      string   `json:"end_date"`
-	Description  string   `json:"description"`
-	Achievements []string `json:"achievements"`
+	ID           string      `json:"id"`
+	Company      string      `json:"company"`
+	Position     string      `json:"position"`
+	StartDate    PartialDate `json:"start_date"`
+	EndDate      PartialDate `json:"end_date"`
+	Description  string      `json:"description"`
+	Achievements []string    `json:"achievements"`
 }
 
 type Project struct {
+	ID          string   `json:"id"`
 	Name        string   `json:"name"`
 	Role        string   `json:"role"`
 	Description string   `json:"description"`
@@ -42,3 +95,90 @@ type Project struct {
 	Highlights  []string `json:"highlights"`
 	URL         string   `json:"url,omitempty"` // 项目URL（可选）
 }
+
+// Certification 证书与资质
+type Certification struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Issuer string      `json:"issuer"` // 颁发机构
+	Date   PartialDate `json:"date"`   // 获得日期
+	URL    string      `json:"url,omitempty"`
+}
+
+// Award 获奖经历
+type Award struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Issuer      string      `json:"issuer"`
+	Date        PartialDate `json:"date"`
+	Description string      `json:"description"`
+}
+
+// Language 语言能力
+type Language struct {
+	ID          string `json:"id"`
+	Language    string `json:"language"`
+	Proficiency string `json:"proficiency"` // 熟练程度，如 母语、CET-6、IELTS 7.0
+}
+
+// Link 个人链接
+type Link struct {
+	ID    string `json:"id"`
+	Label string `json:"label"` // 如 GitHub、博客
+	URL   string `json:"url"`
+}
+
+// Publication 论文与出版物
+type Publication struct {
+	ID          string      `json:"id"`
+	Title       string      `json:"title"`
+	Publisher   string      `json:"publisher"` // 期刊、会议或出版社
+	Date        PartialDate `json:"date"`
+	URL         string      `json:"url,omitempty"`
+	Description string      `json:"description"`
+}
+
+// CustomSection 用户自定义章节，如志愿经历、开源贡献、专利
+type CustomSection struct {
+	ID      string        `json:"id"`
+	Title   string        `json:"title"`
+	Entries []CustomEntry `json:"entries"`
+}
+
+// CustomEntry 自定义章节中的条目
+type CustomEntry struct {
+	ID        string      `json:"id"`
+	Title     string      `json:"title"`
+	Subtitle  string      `json:"subtitle"` // 如组织、角色或专利号
+	StartDate PartialDate `json:"start_date"`
+	EndDate   PartialDate `json:"end_date"`
+	Bullets   []string    `json:"bullets"`
+	URL       string      `json:"url,omitempty"`
+}
+
+// SortSections 将教育经历和工作经历按时间倒序排列：至今的在前，其余按结束日期（没有时按开始日期）从近到远；
+// 没有可识别日期的条目保持原有顺序排在最后
+func SortSections(r *Resume) {
+	slices.SortStableFunc(r.Education, func(a, b Education) int {
+		return compareRecent(a.StartDate, a.EndDate, b.StartDate, b.EndDate)
+	})
+	slices.SortStableFunc(r.Experience, func(a, b Experience) int {
+		return compareRecent(a.StartDate, a.EndDate, b.StartDate, b.EndDate)
+	})
+}
+
+// compareRecent 比较两个时间段，较近的排在前面
+func compareRecent(startA, endA, startB, endB PartialDate) int {
+	if c := latest(startB, endB).Compare(latest(startA, endA)); c != 0 {
+		return c
+	}
+	return startB.Compare(startA)
+}
+
+// latest 时间段中较晚的可识别日期
+func latest(start, end PartialDate) PartialDate {
+	if end.Valid() {
+		return end
+	}
+	return start
+}
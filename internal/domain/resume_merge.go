@@ -0,0 +1,279 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// MergeMode AI生成结果写入已有简历的方式
+type MergeMode string
+
+const (
+	MergeReplace MergeMode = "replace" // 用生成结果整体覆盖
+	MergeMerge   MergeMode = "merge"   // 保留已有条目，仅追加新条目
+	MergePreview MergeMode = "preview" // 按 merge 计算结果但不保存
+)
+
+// ParseMergeMode 解析合并方式，为空时默认为 replace
+func ParseMergeMode(s string) (MergeMode, error) {
+	switch mode := MergeMode(strings.ToLower(strings.TrimSpace(s))); mode {
+	case "":
+		return MergeReplace, nil
+	case MergeReplace, MergeMerge, MergePreview:
+		return mode, nil
+	default:
+		return "", Invalid("mode", fmt.Sprintf("不支持的合并方式: %s（可选 replace、merge、preview）", s))
+	}
+}
+
+// MergeReport 合并结果报告
+type MergeReport struct {
+	Added     []MergeItem     `json:"added"`     // 新增的条目
+	Kept      []MergeItem     `json:"kept"`      // 保留的已有条目
+	Filled    []MergeItem     `json:"filled"`    // 已有条目中被生成结果补全的空字段
+	Conflicts []MergeConflict `json:"conflicts"` // 同一条目字段取值不同，保留了已有值
+}
+
+// MergeItem 报告中的单个条目
+type MergeItem struct {
+	Section string   `json:"section"`
+	Key     string   `json:"key"`
+	Fields  []string `json:"fields,omitempty"`
+}
+
+// MergeConflict 同一条目在已有简历和生成结果中取值不同
+type MergeConflict struct {
+	Section  string   `json:"section"`
+	Key      string   `json:"key"`
+	Fields   []string `json:"fields"`
+	Existing any      `json:"existing"`
+	Incoming any      `json:"incoming"`
+}
+
+// MergeResumes 将 incoming 合并进 existing，返回新的简历（不修改入参）和合并报告。
+// 条目按章节识别：教育经历按学校+专业，工作经历按公司+职位，项目按名称或URL，技能按规范化后的文本，
+// 证书按名称+颁发机构，奖项按名称+颁发机构，语言按语种，链接按URL，出版物按标题或URL。
+// 自定义章节按标题识别，同一章节中的条目按标题+副标题识别。
+// 个人简介已有时保留，与生成结果不同时记为冲突。匹配到的条目保留已有值，仅补全已有值为空的字段；未匹配的新条目追加到末尾
+func MergeResumes(existing, incoming *Resume) (*Resume, MergeReport) {
+	if existing == nil {
+		existing = &Resume{}
+	}
+	if incoming == nil {
+		incoming = &Resume{}
+	}
+
+	merged := *existing
+	report := MergeReport{
+		Added:     []MergeItem{},
+		Kept:      []MergeItem{},
+		Filled:    []MergeItem{},
+		Conflicts: []MergeConflict{},
+	}
+
+	merged.BasicInfo = mergeSection("basic_info", existing.BasicInfo, incoming.BasicInfo, sameBasicInfo, basicInfoLabel, &report)
+	merged.Education = mergeSection("education", existing.Education, incoming.Education, sameEducation, educationKey, &report)
+	merged.Experience = mergeSection("experience", existing.Experience, incoming.Experience, sameExperience, experienceKey, &report)
+	merged.Projects = mergeSection("projects", existing.Projects, incoming.Projects, sameProject, projectLabel, &report)
+	merged.Skills = mergeSection("skills", existing.Skills, incoming.Skills, sameSkill, skillKey, &report)
+	merged.SkillIDs = nil // 保存时按技能文本沿用已有技能的ID
+	merged.Summary = mergeText("summary", existing.Summary, incoming.Summary, &report)
+	merged.Certifications = mergeSection("certifications", existing.Certifications, incoming.Certifications, sameCertification, certificationLabel, &report)
+	merged.Awards = mergeSection("awards", existing.Awards, incoming.Awards, sameAward, awardLabel, &report)
+	merged.Languages = mergeSection("languages", existing.Languages, incoming.Languages, sameLanguage, languageLabel, &report)
+	merged.Links = mergeSection("links", existing.Links, incoming.Links, sameLink, linkLabel, &report)
+	merged.Publications = mergeSection("publications", existing.Publications, incoming.Publications, samePublication, publicationLabel, &report)
+	merged.CustomSections = mergeCustomSections(existing.CustomSections, incoming.CustomSections, &report)
+
+	return &merged, report
+}
+
+// 各章节判断两个条目是否为同一条目
+
+// 个人信息只保留一条，始终视为同一条目
+func sameBasicInfo(a, b BasicInfo) bool { return true }
+
+func sameEducation(a, b Education) bool { return educationKey(a) == educationKey(b) }
+
+func sameExperience(a, b Experience) bool { return experienceKey(a) == experienceKey(b) }
+
+// 项目名称或URL任一相同即视为同一项目
+func sameProject(a, b Project) bool {
+	if a.URL != "" && b.URL != "" && urlKey(a.URL) == urlKey(b.URL) {
+		return true
+	}
+	return a.Name != "" && normalizeKey(a.Name) == normalizeKey(b.Name)
+}
+
+func sameSkill(a, b string) bool { return skillKey(a) == skillKey(b) }
+
+func sameCertification(a, b Certification) bool { return certificationKey(a) == certificationKey(b) }
+
+func sameAward(a, b Award) bool { return awardKey(a) == awardKey(b) }
+
+func sameLanguage(a, b Language) bool { return languageKey(a) == languageKey(b) }
+
+func sameLink(a, b Link) bool { return linkKey(a) == linkKey(b) }
+
+// 出版物标题或URL任一相同即视为同一出版物
+func samePublication(a, b Publication) bool {
+	if a.URL != "" && b.URL != "" && urlKey(a.URL) == urlKey(b.URL) {
+		return true
+	}
+	return a.Title != "" && normalizeKey(a.Title) == normalizeKey(b.Title)
+}
+
+func basicInfoLabel(b BasicInfo) string { return b.Name }
+
+func projectLabel(p Project) string {
+	if p.Name != "" {
+		return p.Name
+	}
+	return p.URL
+}
+
+func sameCustomSection(a, b CustomSection) bool { return customSectionKey(a) == customSectionKey(b) }
+
+func sameCustomEntry(a, b CustomEntry) bool { return customEntryKey(a) == customEntryKey(b) }
+
+func customEntryLabel(e CustomEntry) string { return e.Title }
+
+func certificationLabel(c Certification) string { return c.Name }
+
+func awardLabel(a Award) string { return a.Title }
+
+func languageLabel(l Language) string { return l.Language }
+
+func linkLabel(l Link) string {
+	if l.Label != "" {
+		return l.Label
+	}
+	return l.URL
+}
+
+func publicationLabel(p Publication) string {
+	if p.Title != "" {
+		return p.Title
+	}
+	return p.URL
+}
+
+// mergeText 合并单个文本字段：已有值优先，为空时采用生成结果
+func mergeText(name, existing, incoming string, report *MergeReport) string {
+	if existing == "" {
+		if incoming != "" {
+			report.Added = append(report.Added, MergeItem{Section: name, Key: name})
+		}
+		return incoming
+	}
+	report.Kept = append(report.Kept, MergeItem{Section: name, Key: name})
+	if incoming != "" && incoming != existing {
+		report.Conflicts = append(report.Conflicts, MergeConflict{
+			Section:  name,
+			Key:      name,
+			Fields:   []string{"value"},
+			Existing: existing,
+			Incoming: incoming,
+		})
+	}
+	return existing
+}
+
+// mergeCustomSections 合并自定义章节：新章节追加到末尾，同一章节按条目合并，
+// 报告中条目所在的章节记为 custom:<章节标题>
+func mergeCustomSections(existing, incoming []CustomSection, report *MergeReport) []CustomSection {
+	result := make([]CustomSection, len(existing))
+	copy(result, existing)
+
+	for _, in := range incoming {
+		idx := slices.IndexFunc(result, func(s CustomSection) bool { return sameCustomSection(s, in) })
+		if idx < 0 {
+			result = append(result, in)
+			report.Added = append(report.Added, MergeItem{Section: SectionCustom, Key: in.Title})
+			continue
+		}
+		section := &result[idx]
+		section.Entries = mergeSection(CustomSectionPrefix+section.Title, section.Entries, in.Entries, sameCustomEntry, customEntryLabel, report)
+	}
+
+	for _, s := range existing {
+		report.Kept = append(report.Kept, MergeItem{Section: SectionCustom, Key: s.Title})
+	}
+	return result
+}
+
+// mergeSection 合并单个章节并记录报告
+func mergeSection[T any](name string, existing, incoming []T, same func(a, b T) bool, label func(T) string, report *MergeReport) []T {
+	result := make([]T, len(existing))
+	copy(result, existing)
+	matched := make([]bool, len(result))
+
+	for _, in := range incoming {
+		idx := -1
+		for i := range result {
+			if !matched[i] && same(result[i], in) {
+				idx = i
+				break
+			}
+		}
+		if idx < 0 {
+			result = append(result, in)
+			matched = append(matched, true)
+			report.Added = append(report.Added, MergeItem{Section: name, Key: label(in)})
+			continue
+		}
+
+		matched[idx] = true
+		filled, conflicts := fillEmptyFields(&result[idx], in)
+		if len(filled) > 0 {
+			report.Filled = append(report.Filled, MergeItem{Section: name, Key: label(result[idx]), Fields: filled})
+		}
+		if len(conflicts) > 0 {
+			report.Conflicts = append(report.Conflicts, MergeConflict{
+				Section:  name,
+				Key:      label(result[idx]),
+				Fields:   conflicts,
+				Existing: existing[idx],
+				Incoming: in,
+			})
+		}
+	}
+
+	for i := range existing {
+		report.Kept = append(report.Kept, MergeItem{Section: name, Key: label(existing[i])})
+	}
+	return result
+}
+
+// fillEmptyFields 用 incoming 补全 dst 中为空的字段，返回被补全的字段和取值冲突的字段
+func fillEmptyFields[T any](dst *T, incoming T) (filled, conflicts []string) {
+	dv := reflect.ValueOf(dst).Elem()
+	iv := reflect.ValueOf(incoming)
+	if dv.Kind() != reflect.Struct {
+		// 技能等非结构体条目已按规范化文本匹配，保留原文
+		return nil, nil
+	}
+
+	t := dv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		df, inf := dv.Field(i), iv.Field(i)
+		switch {
+		case isItemID(t.Field(i)):
+			// 保留已有条目的ID
+		case isEmpty(inf) || reflect.DeepEqual(df.Interface(), inf.Interface()):
+		case isEmpty(df):
+			df.Set(inf)
+			filled = append(filled, jsonFieldName(t.Field(i)))
+		default:
+			conflicts = append(conflicts, jsonFieldName(t.Field(i)))
+		}
+	}
+	return filled, conflicts
+}
+
+// isEmpty 零值或空切片
+func isEmpty(v reflect.Value) bool {
+	return v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0)
+}
@@ -0,0 +1,165 @@
+package domain
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestMergeResumes(t *testing.T) {
+	existing := &Resume{
+		ID:        "r1",
+		Version:   3,
+		BasicInfo: []BasicInfo{{Name: "张三", Email: "zhangsan@example.com"}},
+		Summary:   "五年后端经验",
+		Education: []Education{{ID: "e1", School: "北京大学", Major: "计算机科学"}},
+		Experience: []Experience{
+			{ID: "x1", Company: "字节跳动", Position: "后端工程师", Description: "负责网关"},
+		},
+		Projects: []Project{{ID: "p1", Name: "ResumeBuilder", URL: "https://github.com/u/resume"}},
+		Skills:   []string{"Go", "MySQL"},
+		SkillIDs: []string{"s1", "s2"},
+	}
+	incoming := &Resume{
+		BasicInfo: []BasicInfo{{Name: "张三", Phone: "13800000000", Email: "zs@example.com"}},
+		Summary:   "资深后端工程师",
+		Education: []Education{{School: "北京大学", Major: "计算机科学", Degree: "本科"}},
+		Experience: []Experience{
+			{Company: "字节跳动", Position: "后端工程师", Description: "负责网关和鉴权"},
+			{Company: "腾讯", Position: "实习生"},
+		},
+		Projects: []Project{{Name: "简历生成器", URL: "https://GitHub.com/u/resume/", Role: "作者"}},
+		Skills:   []string{"go", "Redis"},
+	}
+	before := *existing
+
+	merged, report := MergeResumes(existing, incoming)
+
+	if !reflect.DeepEqual(*existing, before) {
+		t.Error("MergeResumes 修改了 existing")
+	}
+	if merged.ID != "r1" || merged.Version != 3 {
+		t.Errorf("未保留已有简历的ID和版本: %s, %d", merged.ID, merged.Version)
+	}
+	if b := merged.BasicInfo[0]; len(merged.BasicInfo) != 1 || b.Phone != "13800000000" || b.Email != "zhangsan@example.com" {
+		t.Errorf("BasicInfo = %+v", merged.BasicInfo)
+	}
+	if merged.Summary != "五年后端经验" {
+		t.Errorf("Summary = %q", merged.Summary)
+	}
+	if e := merged.Education[0]; len(merged.Education) != 1 || e.ID != "e1" || e.School != "北京大学" || e.Degree != "本科" {
+		t.Errorf("Education = %+v", merged.Education)
+	}
+	if x := merged.Experience; len(x) != 2 || x[0].Description != "负责网关" || x[1].Company != "腾讯" {
+		t.Errorf("Experience = %+v", x)
+	}
+	if p := merged.Projects; len(p) != 1 || p[0].ID != "p1" || p[0].Name != "ResumeBuilder" || p[0].Role != "作者" {
+		t.Errorf("Projects = %+v", p)
+	}
+	if !reflect.DeepEqual(merged.Skills, []string{"Go", "MySQL", "Redis"}) || merged.SkillIDs != nil {
+		t.Errorf("Skills = %v, SkillIDs = %v", merged.Skills, merged.SkillIDs)
+	}
+
+	wantItems := func(name string, got []MergeItem, want ...string) {
+		t.Helper()
+		keys := []string{}
+		for _, item := range got {
+			keys = append(keys, item.Section+":"+item.Key)
+		}
+		if len(want) == 0 {
+			want = []string{}
+		}
+		if !reflect.DeepEqual(keys, want) {
+			t.Errorf("%s = %q，期望 %q", name, keys, want)
+		}
+	}
+	wantItems("Added", report.Added, "experience:腾讯|实习生", "skills:redis")
+	wantItems("Filled", report.Filled, "basic_info:张三", "education:北京大学|计算机科学", "projects:ResumeBuilder")
+	wantItems("Kept", report.Kept, "basic_info:张三", "education:北京大学|计算机科学", "experience:字节跳动|后端工程师",
+		"projects:ResumeBuilder", "skills:go", "skills:mysql", "summary:summary")
+
+	conflicts := map[string][]string{}
+	for _, c := range report.Conflicts {
+		conflicts[c.Section] = c.Fields
+	}
+	wantConflicts := map[string][]string{
+		"basic_info": {"email"},
+		"summary":    {"value"},
+		"experience": {"description"},
+		"projects":   {"name", "url"},
+	}
+	if !reflect.DeepEqual(conflicts, wantConflicts) {
+		t.Errorf("Conflicts = %v，期望 %v", conflicts, wantConflicts)
+	}
+}
+
+func TestMergeResumesEmpty(t *testing.T) {
+	incoming := &Resume{Skills: []string{"Go"}, Summary: "简介"}
+	merged, report := MergeResumes(nil, incoming)
+	if !reflect.DeepEqual(merged.Skills, []string{"Go"}) || merged.Summary != "简介" {
+		t.Errorf("合并到空简历 = %+v", merged)
+	}
+	if len(report.Added) != 2 || len(report.Kept) != 0 || report.Conflicts == nil {
+		t.Errorf("report = %+v", report)
+	}
+
+	merged, report = MergeResumes(incoming, nil)
+	if !reflect.DeepEqual(merged.Skills, []string{"Go"}) || len(report.Added) != 0 || len(report.Kept) != 2 {
+		t.Errorf("合并空结果 = %+v, %+v", merged, report)
+	}
+}
+
+func TestMergeCustomSections(t *testing.T) {
+	existing := &Resume{CustomSections: []CustomSection{
+		{ID: "c1", Title: "开源贡献", Entries: []CustomEntry{{ID: "ce1", Title: "gorm", Subtitle: "Contributor"}}},
+	}}
+	incoming := &Resume{CustomSections: []CustomSection{
+		{Title: "开源贡献", Entries: []CustomEntry{
+			{Title: "gorm", Subtitle: "contributor", URL: "https://github.com/go-gorm/gorm"},
+			{Title: "gin", Subtitle: "Contributor"},
+		}},
+		{Title: "志愿经历", Entries: []CustomEntry{{Title: "支教"}}},
+	}}
+
+	merged, report := MergeResumes(existing, incoming)
+	sections := merged.CustomSections
+	if len(sections) != 2 || sections[0].ID != "c1" || sections[1].Title != "志愿经历" {
+		t.Fatalf("CustomSections = %+v", sections)
+	}
+	if e := sections[0].Entries; len(e) != 2 || e[0].ID != "ce1" || e[0].URL == "" || e[1].Title != "gin" {
+		t.Errorf("开源贡献 = %+v", e)
+	}
+	if len(existing.CustomSections[0].Entries) != 1 || existing.CustomSections[0].Entries[0].URL != "" {
+		t.Error("MergeResumes 修改了 existing")
+	}
+	added := []string{}
+	for _, item := range report.Added {
+		added = append(added, item.Section+":"+item.Key)
+	}
+	if want := []string{"custom:开源贡献:gin", SectionCustom + ":志愿经历"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("Added = %q，期望 %q", added, want)
+	}
+}
+
+func TestParseMergeMode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want MergeMode
+	}{
+		{"", MergeReplace},
+		{"replace", MergeReplace},
+		{" Merge ", MergeMerge},
+		{"PREVIEW", MergePreview},
+	}
+	for _, tt := range tests {
+		if got, err := ParseMergeMode(tt.in); err != nil || got != tt.want {
+			t.Errorf("ParseMergeMode(%q) = %q, %v，期望 %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	_, err := ParseMergeMode("append")
+	var invalid *ValidationError
+	if !errors.As(err, &invalid) {
+		t.Errorf("ParseMergeMode(append) = %v，期望 *ValidationError", err)
+	}
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// RevisionSource 简历修订的来源
+type RevisionSource string
+
+const (
+	SourceManual       RevisionSource = "manual"        // 用户手动保存
+	SourceAIGenerate   RevisionSource = "ai_generate"   // AI根据文本生成
+	SourceGitHubImport RevisionSource = "github_import" // 导入GitHub项目
+	SourceRestore      RevisionSource = "restore"       // 从历史修订恢复
+	SourceClone        RevisionSource = "clone"         // 从其他简历复制
+	SourcePatch        RevisionSource = "patch"         // 局部修改（JSON Merge Patch 或按条目编辑）
+)
+
+// Revision 简历的一次修订
+// 修订号在用户维度内递增，因此同一用户的不同简历不会出现相同的修订号
+type Revision struct {
+	UserID    string         `json:"user_id"`
+	ResumeID  string         `json:"resume_id"`
+	Revision  int            `json:"revision"`
+	Source    RevisionSource `json:"source"`
+	CreatedAt time.Time      `json:"created_at"`
+	Resume    *Resume        `json:"resume,omitempty"` // 列表接口中不返回完整内容
+}
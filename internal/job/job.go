@@ -0,0 +1,44 @@
+package job
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// Status 任务状态
+type Status string
+
+const (
+	StatusQueued    Status = "queued"    // 排队中（含等待重试）
+	StatusRunning   Status = "running"   // 执行中
+	StatusSucceeded Status = "succeeded" // 执行成功，Result 为结果
+	StatusFailed    Status = "failed"    // 重试次数用尽仍失败，Error 为最后一次的错误
+	StatusCanceled  Status = "canceled"  // 已取消
+)
+
+// Finished 任务是否已结束（不会再被执行）
+func (s Status) Finished() bool {
+	return s == StatusSucceeded || s == StatusFailed || s == StatusCanceled
+}
+
+var (
+	ErrNotFound    = errors.New("任务不存在或已过期")
+	ErrFinished    = errors.New("任务已结束，无法取消")
+	ErrUnknownType = errors.New("不支持的任务类型")
+)
+
+// Job 一个异步任务及其执行状态
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	UserID      string          `json:"user_id"`
+	Status      Status          `json:"status"`
+	Payload     json.RawMessage `json:"payload,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	Error       string          `json:"error,omitempty"`
+	Attempts    int             `json:"attempts"`     // 已开始执行的次数
+	MaxAttempts int             `json:"max_attempts"` // 最多执行次数（含首次）
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
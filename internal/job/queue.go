@@ -0,0 +1,502 @@
+package job
+
+import (
+	"ResumeBuilder/internal/config"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	queueKey      = "jobs:queue"      // 待执行的任务ID，LPUSH 入队，从右端取出
+	processingKey = "jobs:processing" // 已被 worker 取走、正在执行的任务ID
+	delayedKey    = "jobs:delayed"    // 等待重试的任务ID，score 为可执行时间（毫秒时间戳）
+
+	// leaseTTL worker 执行任务期间持续续期租约，租约过期说明 worker 已退出，任务会被重新入队
+	leaseTTL = 30 * time.Second
+	// pollInterval 取任务、检查取消标记和调度延迟任务的间隔
+	pollInterval = time.Second
+	// maxUpdateRetries 并发修改同一任务时乐观锁的重试次数
+	maxUpdateRetries = 10
+)
+
+// leaseSuffix 租约键为 job:{id}:lease
+const leaseSuffix = ":lease"
+
+func jobKey(id string) string    { return "job:" + id }
+func leaseKey(id string) string  { return jobKey(id) + leaseSuffix }
+func cancelKey(id string) string { return "job:" + id + ":cancel" }
+
+// errSkip 任务状态已变化，放弃本次修改
+var errSkip = errors.New("skip")
+
+// errCanceled 任务被用户取消
+var errCanceled = errors.New("任务已取消")
+
+// promoteScript 将到期的重试任务原子地移回待执行队列
+var promoteScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1])
+for _, id in ipairs(ids) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('LPUSH', KEYS[2], id)
+end
+return #ids
+`)
+
+// claimScript 从待执行队列取出一个任务ID放入执行中列表，同时加上租约。
+// 两步必须原子执行，否则回收任务时可能把刚取出、尚未加上租约的任务当作 worker 已退出而重新入队
+var claimScript = redis.NewScript(`
+local id = redis.call('LMOVE', KEYS[1], KEYS[2], 'RIGHT', 'LEFT')
+if not id then
+	return false
+end
+redis.call('SET', ARGV[1] .. id .. ARGV[2], 1, 'PX', ARGV[3])
+return id
+`)
+
+// Handler 执行一种类型的任务，返回值序列化为 JSON 后作为任务结果。
+// ctx 在任务超时、被取消或服务退出时结束；任务失败后可能被重试，处理函数需保证重复执行是安全的
+type Handler func(ctx context.Context, j *Job) (any, error)
+
+// Queue 基于 Redis 的异步任务队列。任务状态保存在 job:{id} 中，保留 ResultTTL 时长；
+// 多个服务实例可以共用同一个队列
+type Queue struct {
+	redis    *redis.Client
+	cfg      config.JobConfig
+	handlers map[string]Handler
+
+	wg sync.WaitGroup
+	// poll 结束后 worker 不再领取新任务
+	poll     context.Context
+	stopPoll context.CancelFunc
+	// exec 为正在执行的任务的父 ctx，结束后任务被中断并放回队列
+	exec      context.Context
+	abortExec context.CancelFunc
+}
+
+// NewQueue 创建任务队列，需先 Register 各类型的处理函数再调用 Start
+func NewQueue(client *redis.Client, cfg config.JobConfig) *Queue {
+	return &Queue{
+		redis:    client,
+		cfg:      cfg,
+		handlers: map[string]Handler{},
+	}
+}
+
+// Register 注册任务类型的处理函数，非并发安全，应在 Start 之前调用
+func (q *Queue) Register(typ string, h Handler) {
+	q.handlers[typ] = h
+}
+
+// Enqueue 创建任务并加入队列
+func (q *Queue) Enqueue(ctx context.Context, typ, userID string, payload any) (*Job, error) {
+	if _, ok := q.handlers[typ]; !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, typ)
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	j := &Job{
+		ID:          uuid.NewString(),
+		Type:        typ,
+		UserID:      userID,
+		Status:      StatusQueued,
+		Payload:     data,
+		MaxAttempts: q.cfg.MaxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	b, err := json.Marshal(j)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := q.redis.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.Set(ctx, jobKey(j.ID), b, q.cfg.ResultTTL)
+		p.LPush(ctx, queueKey, j.ID)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("任务入队失败: %w", err)
+	}
+	return j, nil
+}
+
+// Get 查询任务状态和结果
+func (q *Queue) Get(ctx context.Context, id string) (*Job, error) {
+	data, err := q.redis.Get(ctx, jobKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var j Job
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+// Cancel 取消任务。排队中的任务立即取消；执行中的任务会在下一次检查取消标记时中断，
+// 此时返回的任务状态仍为 running
+func (q *Queue) Cancel(ctx context.Context, id string) (*Job, error) {
+	return q.update(ctx, id, func(j *Job, p redis.Pipeliner) error {
+		switch {
+		case j.Status.Finished():
+			return ErrFinished
+		case j.Status == StatusQueued:
+			// 待执行队列中的任务ID由 worker 取出后跳过
+			j.Status = StatusCanceled
+			p.ZRem(ctx, delayedKey, id)
+		default:
+			p.Set(ctx, cancelKey(id), 1, q.cfg.Timeout+leaseTTL)
+		}
+		return nil
+	})
+}
+
+// update 以乐观锁读取、修改并写回任务。fn 可以向 p 追加需要与状态变更原子执行的命令，
+// 返回错误时放弃修改
+func (q *Queue) update(ctx context.Context, id string, fn func(j *Job, p redis.Pipeliner) error) (*Job, error) {
+	var updated *Job
+	txf := func(tx *redis.Tx) error {
+		data, err := tx.Get(ctx, jobKey(id)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			return ErrNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		var j Job
+		if err := json.Unmarshal(data, &j); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(p redis.Pipeliner) error {
+			if err := fn(&j, p); err != nil {
+				return err
+			}
+			j.UpdatedAt = time.Now()
+			b, err := json.Marshal(&j)
+			if err != nil {
+				return err
+			}
+			p.Set(ctx, jobKey(id), b, q.cfg.ResultTTL)
+			return nil
+		})
+		if err == nil {
+			updated = &j
+		}
+		return err
+	}
+
+	for range maxUpdateRetries {
+		err := q.redis.Watch(ctx, txf, jobKey(id))
+		if errors.Is(err, redis.TxFailedErr) {
+			continue
+		}
+		return updated, err
+	}
+	return nil, errors.New("任务状态更新冲突，请稍后重试")
+}
+
+// Start 在后台启动 worker 和调度循环，Workers 为 0 时本实例只接收任务，不执行任务
+func (q *Queue) Start() {
+	q.poll, q.stopPoll = context.WithCancel(context.Background())
+	q.exec, q.abortExec = context.WithCancel(context.Background())
+
+	for range q.cfg.Workers {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			q.work()
+		}()
+	}
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		q.schedule(q.poll)
+	}()
+}
+
+// Shutdown 停止领取新任务并等待执行中的任务完成。ctx 结束时仍未完成的任务被中断，
+// 放回队列且不计入执行次数，由其他实例或重启后继续执行；尚未开始的任务始终保留在 Redis 中
+func (q *Queue) Shutdown(ctx context.Context) error {
+	if q.stopPoll == nil {
+		return nil
+	}
+	q.stopPoll()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		q.abortExec()
+		return nil
+	case <-ctx.Done():
+		q.abortExec()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work 循环取出并执行任务，直到停止领取；队列为空时每隔 pollInterval 检查一次
+func (q *Queue) work() {
+	for q.poll.Err() == nil {
+		id, err := q.claim(q.poll)
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && q.poll.Err() == nil {
+				log.Printf("⚠️ 读取任务队列失败: %v\n", err)
+			}
+			sleep(q.poll, pollInterval)
+			continue
+		}
+		q.process(q.exec, id)
+	}
+}
+
+// claim 取出一个任务并加上租约，队列为空时返回 redis.Nil
+func (q *Queue) claim(ctx context.Context) (string, error) {
+	return claimScript.Run(ctx, q.redis, []string{queueKey, processingKey},
+		jobKey(""), leaseSuffix, leaseTTL.Milliseconds()).Text()
+}
+
+// process 执行单个任务并记录结果；失败时按配置重试
+func (q *Queue) process(ctx context.Context, id string) {
+	// 服务退出时 ctx 已结束，收尾写入需使用不会被取消的 ctx
+	store := context.WithoutCancel(ctx)
+
+	j, err := q.update(ctx, id, func(j *Job, p redis.Pipeliner) error {
+		if j.Status != StatusQueued {
+			return errSkip
+		}
+		j.Status = StatusRunning
+		j.Attempts++
+		return nil
+	})
+	switch {
+	case errors.Is(err, ErrNotFound):
+		q.release(store, id)
+		return
+	case errors.Is(err, errSkip):
+		// 任务已取消或正由其他 worker 执行：租约和执行中列表可能属于对方，不做修改，
+		// 本次取出留下的记录在租约过期后由 reap 清理
+		return
+	case err != nil:
+		// 任务仍为排队状态，租约过期后由 reap 放回队列
+		log.Printf("⚠️ 任务 %s 启动失败: %v\n", id, err)
+		return
+	}
+	defer q.release(store, id)
+
+	handler, ok := q.handlers[j.Type]
+	if !ok {
+		q.finish(store, id, StatusFailed, nil, fmt.Errorf("%w: %s", ErrUnknownType, j.Type))
+		return
+	}
+
+	runCtx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	jobCtx, stop := context.WithTimeout(runCtx, q.cfg.Timeout)
+	defer stop()
+	go q.heartbeat(jobCtx, id, cancel)
+
+	result, err := handler(jobCtx, j)
+	timedOut := errors.Is(jobCtx.Err(), context.DeadlineExceeded)
+	stop()
+
+	switch {
+	case err == nil:
+		data, merr := json.Marshal(result)
+		if merr != nil {
+			q.finish(store, id, StatusFailed, nil, merr)
+			return
+		}
+		q.finish(store, id, StatusSucceeded, data, nil)
+	case errors.Is(context.Cause(runCtx), errCanceled):
+		q.finish(store, id, StatusCanceled, nil, errCanceled)
+	case ctx.Err() != nil:
+		// 服务退出导致的中断不计入执行次数，放回队首由其他实例或重启后继续执行
+		q.requeue(store, id)
+	default:
+		if timedOut {
+			err = fmt.Errorf("任务执行超时（%s）: %w", q.cfg.Timeout, err)
+		}
+		log.Printf("⚠️ 任务 %s 第 %d 次执行失败: %v\n", id, j.Attempts, err)
+		q.retry(store, id, err)
+	}
+}
+
+// release 任务执行结束，移出执行中列表后再释放租约，避免 reap 在两步之间回收该任务
+func (q *Queue) release(ctx context.Context, id string) {
+	q.redis.LRem(ctx, processingKey, 1, id)
+	q.redis.Del(ctx, leaseKey(id))
+}
+
+// heartbeat 定期续期租约并检查取消标记，直到 ctx 结束
+func (q *Queue) heartbeat(ctx context.Context, id string, cancel context.CancelCauseFunc) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			q.redis.Expire(ctx, leaseKey(id), leaseTTL)
+			if n, _ := q.redis.Exists(ctx, cancelKey(id)).Result(); n > 0 {
+				cancel(errCanceled)
+				return
+			}
+		}
+	}
+}
+
+// finish 将执行中的任务标记为结束状态
+func (q *Queue) finish(ctx context.Context, id string, status Status, result json.RawMessage, cause error) {
+	_, err := q.update(ctx, id, func(j *Job, p redis.Pipeliner) error {
+		if j.Status != StatusRunning {
+			return errSkip
+		}
+		j.Status = status
+		j.Result = result
+		j.Error = ""
+		if cause != nil {
+			j.Error = cause.Error()
+		}
+		p.Del(ctx, cancelKey(id))
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSkip) && !errors.Is(err, ErrNotFound) {
+		log.Printf("⚠️ 任务 %s 状态写入失败: %v\n", id, err)
+	}
+}
+
+// retry 执行失败的任务在次数未用尽时延迟重试，否则标记为失败；已请求取消的任务直接取消
+func (q *Queue) retry(ctx context.Context, id string, cause error) {
+	if n, _ := q.redis.Exists(ctx, cancelKey(id)).Result(); n > 0 {
+		q.finish(ctx, id, StatusCanceled, nil, errCanceled)
+		return
+	}
+
+	_, err := q.update(ctx, id, func(j *Job, p redis.Pipeliner) error {
+		if j.Status != StatusRunning {
+			return errSkip
+		}
+		j.Error = cause.Error()
+		if j.Attempts >= j.MaxAttempts {
+			j.Status = StatusFailed
+			return nil
+		}
+
+		// 重试间隔逐次翻倍
+		j.Status = StatusQueued
+		delay := q.cfg.RetryBackoff << (j.Attempts - 1)
+		p.ZAdd(ctx, delayedKey, redis.Z{
+			Score:  float64(time.Now().Add(delay).UnixMilli()),
+			Member: id,
+		})
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSkip) && !errors.Is(err, ErrNotFound) {
+		log.Printf("⚠️ 任务 %s 重试入队失败: %v\n", id, err)
+	}
+}
+
+// requeue 将执行中的任务放回队首，不计入执行次数
+func (q *Queue) requeue(ctx context.Context, id string) {
+	_, err := q.update(ctx, id, func(j *Job, p redis.Pipeliner) error {
+		if j.Status != StatusRunning {
+			return errSkip
+		}
+		j.Status = StatusQueued
+		j.Attempts--
+		p.RPush(ctx, queueKey, id)
+		return nil
+	})
+	if err != nil && !errors.Is(err, errSkip) && !errors.Is(err, ErrNotFound) {
+		log.Printf("⚠️ 任务 %s 重新入队失败: %v\n", id, err)
+	}
+}
+
+// schedule 定期将到期的重试任务移回队列，并回收租约过期的任务
+func (q *Queue) schedule(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+			if err := promoteScript.Run(ctx, q.redis, []string{delayedKey, queueKey}, now).Err(); err != nil && ctx.Err() == nil {
+				log.Printf("⚠️ 调度重试任务失败: %v\n", err)
+			}
+			q.reap(ctx)
+		}
+	}
+}
+
+// reap 回收执行中但租约已过期的任务（worker 崩溃或实例被强制终止）
+func (q *Queue) reap(ctx context.Context) {
+	ids, err := q.redis.LRange(ctx, processingKey, 0, -1).Result()
+	if err != nil {
+		return
+	}
+
+	for _, id := range ids {
+		if n, _ := q.redis.Exists(ctx, leaseKey(id)).Result(); n > 0 {
+			continue
+		}
+		j, err := q.Get(ctx, id)
+		if errors.Is(err, ErrNotFound) {
+			q.redis.LRem(ctx, processingKey, 1, id)
+			continue
+		}
+		// 刚更新过状态的任务（如刚被 worker 放回队列）留给 worker 收尾
+		if err != nil || time.Since(j.UpdatedAt) < leaseTTL {
+			continue
+		}
+		// 多个实例同时回收时只有移除成功的一方继续处理
+		if n, _ := q.redis.LRem(ctx, processingKey, 1, id).Result(); n == 0 {
+			continue
+		}
+
+		switch j.Status {
+		case StatusQueued:
+			q.redis.RPush(ctx, queueKey, id)
+		case StatusRunning:
+			log.Printf("⚠️ 任务 %s 租约过期，重新调度\n", id)
+			q.retry(ctx, id, errors.New("执行任务的服务实例已退出"))
+		}
+	}
+}
+
+// sleep 等待 d 或直到 ctx 结束
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
@@ -0,0 +1,243 @@
+package job
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/domain"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// newTestQueue 创建使用 miniredis 的队列，不启动 worker，由测试直接调用 claim、process 和 reap
+func newTestQueue(t *testing.T, h Handler) (*Queue, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	q := NewQueue(client, config.JobConfig{
+		MaxAttempts:  3,
+		RetryBackoff: time.Second,
+		Timeout:      10 * time.Second,
+		ResultTTL:    time.Hour,
+	})
+	q.Register("test", h)
+	return q, mr
+}
+
+// mustGet 读取任务，失败时终止测试
+func mustGet(t *testing.T, q *Queue, id string) *Job {
+	t.Helper()
+	j, err := q.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Get(%q): %v", id, err)
+	}
+	return j
+}
+
+// mustClaim 取出一个任务，期望为 want
+func mustClaim(t *testing.T, q *Queue, want string) {
+	t.Helper()
+	id, err := q.claim(context.Background())
+	if err != nil || id != want {
+		t.Fatalf("claim = %q, %v，期望 %q", id, err, want)
+	}
+}
+
+// backdate 将任务的状态改为 status，更新时间改为 age 之前，模拟 worker 在执行中退出
+func backdate(t *testing.T, q *Queue, id string, status Status, age time.Duration) {
+	t.Helper()
+	j := mustGet(t, q, id)
+	j.Status = status
+	if status == StatusRunning {
+		j.Attempts++
+	}
+	j.UpdatedAt = time.Now().Add(-age)
+	b, _ := json.Marshal(j)
+	if err := q.redis.Set(context.Background(), jobKey(id), b, time.Hour).Err(); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+}
+
+func succeed(context.Context, *Job) (any, error) { return "ok", nil }
+
+func TestClaim(t *testing.T) {
+	ctx := context.Background()
+	q, mr := newTestQueue(t, succeed)
+
+	if _, err := q.claim(ctx); !errors.Is(err, redis.Nil) {
+		t.Fatalf("队列为空时 claim 的错误为 %v，期望 redis.Nil", err)
+	}
+
+	first, _ := q.Enqueue(ctx, "test", "u1", nil)
+	second, _ := q.Enqueue(ctx, "test", "u1", nil)
+	mustClaim(t, q, first.ID)
+
+	if ttl := mr.TTL(leaseKey(first.ID)); ttl <= 0 || ttl > leaseTTL {
+		t.Errorf("租约的剩余时间为 %v，期望在 (0, %v] 内", ttl, leaseTTL)
+	}
+	if mr.Exists(leaseKey(second.ID)) {
+		t.Error("未取出的任务不应有租约")
+	}
+	if got, _ := mr.List(processingKey); len(got) != 1 || got[0] != first.ID {
+		t.Errorf("执行中列表 = %v", got)
+	}
+	if got, _ := mr.List(queueKey); len(got) != 1 || got[0] != second.ID {
+		t.Errorf("待执行队列 = %v", got)
+	}
+}
+
+func TestReap(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      Status        // 回收前任务的状态，为空表示不修改
+		age         time.Duration // 任务距上次更新的时间
+		expire      bool          // 租约是否已过期
+		wantStatus  Status
+		wantQueue   bool // 是否放回了待执行队列
+		wantDelayed bool // 是否进入了延迟重试队列
+		wantKept    bool // 是否仍留在执行中列表
+	}{
+		{"租约未过期", StatusRunning, time.Minute, false, StatusRunning, false, false, true},
+		{"刚更新过状态的任务留给 worker 收尾", StatusRunning, 0, true, StatusRunning, false, false, true},
+		{"执行中的任务延迟重试", StatusRunning, time.Minute, true, StatusQueued, false, true, false},
+		{"尚未开始执行的任务放回队列", StatusQueued, time.Minute, true, StatusQueued, true, false, false},
+		{"已结束的任务不再执行", StatusSucceeded, time.Minute, true, StatusSucceeded, false, false, false},
+		{"已取消的任务不再执行", StatusCanceled, time.Minute, true, StatusCanceled, false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			q, mr := newTestQueue(t, succeed)
+			j, _ := q.Enqueue(ctx, "test", "u1", nil)
+			mustClaim(t, q, j.ID)
+			backdate(t, q, j.ID, tt.status, tt.age)
+			if tt.expire {
+				mr.FastForward(leaseTTL + time.Second)
+			}
+
+			q.reap(ctx)
+
+			if got := mustGet(t, q, j.ID); got.Status != tt.wantStatus {
+				t.Errorf("Status = %s，期望 %s", got.Status, tt.wantStatus)
+			}
+			if queued, _ := mr.List(queueKey); (len(queued) == 1) != tt.wantQueue {
+				t.Errorf("待执行队列 = %v", queued)
+			}
+			if delayed, _ := mr.ZMembers(delayedKey); (len(delayed) == 1) != tt.wantDelayed {
+				t.Errorf("延迟重试队列 = %v", delayed)
+			}
+			if processing, _ := mr.List(processingKey); (len(processing) == 1) != tt.wantKept {
+				t.Errorf("执行中列表 = %v", processing)
+			}
+		})
+	}
+}
+
+func TestProcess(t *testing.T) {
+	ctx := context.Background()
+	q, mr := newTestQueue(t, succeed)
+	j, _ := q.Enqueue(ctx, "test", "u1", nil)
+	mustClaim(t, q, j.ID)
+	q.process(ctx, j.ID)
+
+	got := mustGet(t, q, j.ID)
+	if got.Status != StatusSucceeded || string(got.Result) != `"ok"` || got.Attempts != 1 {
+		t.Fatalf("执行后任务 = %+v", got)
+	}
+	if mr.Exists(leaseKey(j.ID)) || mr.Exists(processingKey) {
+		t.Error("执行结束后未释放租约或未移出执行中列表")
+	}
+	if _, err := q.claim(ctx); !errors.Is(err, redis.Nil) {
+		t.Errorf("执行成功的任务被再次取出: %v", err)
+	}
+}
+
+func TestProcessSkipped(t *testing.T) {
+	ctx := context.Background()
+	called := false
+	q, mr := newTestQueue(t, func(context.Context, *Job) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	// 排队中取消的任务ID仍在队列中，被取出后跳过
+	j, _ := q.Enqueue(ctx, "test", "u1", nil)
+	if _, err := q.Cancel(ctx, j.ID); err != nil {
+		t.Fatalf("Cancel: %v", err)
+	}
+	mustClaim(t, q, j.ID)
+	q.process(ctx, j.ID)
+
+	if called {
+		t.Error("已取消的任务不应执行")
+	}
+	if got := mustGet(t, q, j.ID); got.Status != StatusCanceled || got.Attempts != 0 {
+		t.Errorf("任务 = %+v", got)
+	}
+	// 跳过时不修改租约和执行中列表，它们可能属于正在执行该任务的其他 worker
+	if !mr.Exists(leaseKey(j.ID)) {
+		t.Error("跳过的任务的租约被删除")
+	}
+	if processing, _ := mr.List(processingKey); len(processing) != 1 {
+		t.Errorf("执行中列表 = %v", processing)
+	}
+
+	// 租约过期后由 reap 清理，任务不会再被执行
+	mr.FastForward(leaseTTL + time.Second)
+	backdate(t, q, j.ID, StatusCanceled, time.Minute)
+	q.reap(ctx)
+	if mr.Exists(processingKey) {
+		t.Error("reap 未清理跳过的任务")
+	}
+	if _, err := q.claim(ctx); !errors.Is(err, redis.Nil) {
+		t.Errorf("已取消的任务被再次取出: %v", err)
+	}
+}
+
+func TestProcessRetryAlreadyApplied(t *testing.T) {
+	ctx := context.Background()
+	d := dao.NewMemoryDAO()
+	var errs []error
+	q, mr := newTestQueue(t, func(ctx context.Context, j *Job) (any, error) {
+		err := d.Create(dao.WithJobID(ctx, j.ID), &domain.Resume{UserID: j.UserID}, domain.SourceAIGenerate)
+		errs = append(errs, err)
+		if len(errs) == 1 {
+			// 结果已写入，但在记录任务状态之前失败
+			return nil, errors.New("连接中断")
+		}
+		return nil, err
+	})
+
+	j, _ := q.Enqueue(ctx, "test", "u1", nil)
+	mustClaim(t, q, j.ID)
+	q.process(ctx, j.ID)
+	if got := mustGet(t, q, j.ID); got.Status != StatusQueued || got.Attempts != 1 {
+		t.Fatalf("第一次执行失败后任务 = %+v", got)
+	}
+
+	// 重试到期后移回队列再次执行
+	mr.FastForward(2 * q.cfg.RetryBackoff)
+	later := time.Now().Add(2 * q.cfg.RetryBackoff).UnixMilli()
+	if err := promoteScript.Run(ctx, q.redis, []string{delayedKey, queueKey}, later).Err(); err != nil {
+		t.Fatalf("promote: %v", err)
+	}
+	mustClaim(t, q, j.ID)
+	q.process(ctx, j.ID)
+
+	if len(errs) != 2 || errs[0] != nil || !errors.Is(errs[1], dao.ErrJobAlreadyApplied) {
+		t.Fatalf("两次写入的结果 = %v，期望第二次为 ErrJobAlreadyApplied", errs)
+	}
+	if list, _ := d.List(ctx, "u1"); len(list) != 1 {
+		t.Errorf("同一任务写入了 %d 份简历", len(list))
+	}
+	if got := mustGet(t, q, j.ID); got.Attempts != 2 {
+		t.Errorf("任务 = %+v", got)
+	}
+}
@@ -0,0 +1,164 @@
+// Package migration 管理数据库结构的版本：迁移按版本号依次执行，已执行的版本记录在 schema_migrations 表中。
+// 迁移只能追加，已发布的迁移不得修改
+package migration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSchemaBehind 数据库结构落后于程序，需要先执行 migrate up
+var ErrSchemaBehind = errors.New("数据库结构版本落后")
+
+// Migration 一次数据库结构变更
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error // 为 nil 时表示不可回滚
+}
+
+// Status 迁移的执行状态
+type Status struct {
+	Version   int
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// schemaMigration 已执行的迁移记录
+type schemaMigration struct {
+	Version   int    `gorm:"primaryKey;autoIncrement:false"`
+	Name      string `gorm:"size:128;not null"`
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// applied 已执行的迁移，按版本号升序
+func applied(ctx context.Context, db *gorm.DB) ([]schemaMigration, error) {
+	if err := db.WithContext(ctx).AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, fmt.Errorf("创建迁移记录表失败: %w", err)
+	}
+	var rows []schemaMigration
+	if err := db.WithContext(ctx).Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// pending 尚未执行的迁移，按版本号升序
+func pending(ctx context.Context, db *gorm.DB) ([]Migration, error) {
+	rows, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+	var result []Migration
+	for _, m := range migrations {
+		if !slices.ContainsFunc(rows, func(row schemaMigration) bool { return row.Version == m.Version }) {
+			result = append(result, m)
+		}
+	}
+	return result, nil
+}
+
+// Up 依次执行全部未执行的迁移，返回本次执行的迁移。
+// 每个迁移与其执行记录在同一事务中提交；MySQL 的 DDL 会隐式提交事务，失败的迁移需要手动检查。
+// 不应有多个进程同时执行迁移
+func Up(ctx context.Context, db *gorm.DB) ([]Migration, error) {
+	todo, err := pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range todo {
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("执行迁移 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down 按版本号倒序回滚最近执行的 steps 个迁移，返回本次回滚的迁移
+func Down(ctx context.Context, db *gorm.DB, steps int) ([]Migration, error) {
+	rows, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(rows) - 1; i >= 0 && len(done) < steps; i-- {
+		idx := slices.IndexFunc(migrations, func(m Migration) bool { return m.Version == rows[i].Version })
+		if idx < 0 {
+			return done, fmt.Errorf("迁移 %d_%s 不属于当前程序版本，无法回滚", rows[i].Version, rows[i].Name)
+		}
+		m := migrations[idx]
+		if m.Down == nil {
+			return done, fmt.Errorf("迁移 %d_%s 不可回滚", m.Version, m.Name)
+		}
+
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := m.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("回滚迁移 %d_%s 失败: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// List 返回全部迁移及其执行状态，按版本号升序；数据库中有而程序中没有的迁移（由更新的程序版本执行）也会列出
+func List(ctx context.Context, db *gorm.DB) ([]Status, error) {
+	rows, err := applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []Status
+	for _, m := range migrations {
+		s := Status{Version: m.Version, Name: m.Name}
+		if idx := slices.IndexFunc(rows, func(row schemaMigration) bool { return row.Version == m.Version }); idx >= 0 {
+			s.Applied, s.AppliedAt = true, rows[idx].AppliedAt
+		}
+		result = append(result, s)
+	}
+	for _, row := range rows {
+		if !slices.ContainsFunc(migrations, func(m Migration) bool { return m.Version == row.Version }) {
+			result = append(result, Status{Version: row.Version, Name: row.Name, Applied: true, AppliedAt: row.AppliedAt})
+		}
+	}
+	slices.SortFunc(result, func(a, b Status) int { return a.Version - b.Version })
+	return result, nil
+}
+
+// Check 检查数据库结构是否为最新，有未执行的迁移时返回 ErrSchemaBehind
+func Check(ctx context.Context, db *gorm.DB) error {
+	todo, err := pending(ctx, db)
+	if err != nil {
+		return err
+	}
+	if len(todo) > 0 {
+		return fmt.Errorf("%w：有 %d 个迁移未执行（最早为 %d_%s），请先运行 migrate up",
+			ErrSchemaBehind, len(todo), todo[0].Version, todo[0].Name)
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+package migration
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// openSQLite 打开空的内存 SQLite 数据库，命名规则与 dao.OpenDB 相同
+func openSQLite(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		NamingStrategy: schema.NamingStrategy{SingularTable: true},
+	})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	// 内存数据库只在同一连接内可见
+	sqlDB, _ := db.DB()
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+// resumeIndexes 简历表上的索引名，按名称排序
+func resumeIndexes(t *testing.T, db *gorm.DB) []string {
+	t.Helper()
+	var names []string
+	if err := db.Raw(`SELECT name FROM sqlite_master
+		WHERE type = 'index' AND tbl_name = 'resume_model' AND name NOT LIKE 'sqlite_%'`).
+		Scan(&names).Error; err != nil {
+		t.Fatalf("查询索引: %v", err)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// insertResume 以当前表结构插入一份简历
+func insertResume(db *gorm.DB, resumeID, userID string) error {
+	return db.Exec("INSERT INTO resume_model (resume_id, user_id, name, is_default, version) VALUES (?, ?, '默认简历', false, 1)",
+		resumeID, userID).Error
+}
+
+func TestUpDown(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+
+	done, err := Up(ctx, db)
+	if err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if len(done) != len(migrations) {
+		t.Fatalf("Up 执行了 %d 个迁移，期望 %d 个", len(done), len(migrations))
+	}
+	if err := Check(ctx, db); err != nil {
+		t.Fatalf("Up 后 Check: %v", err)
+	}
+	if done, err := Up(ctx, db); err != nil || len(done) != 0 {
+		t.Fatalf("重复执行 Up = %d, %v", len(done), err)
+	}
+	if err := insertResume(db, "r1", "u1"); err != nil {
+		t.Fatalf("插入简历: %v", err)
+	}
+
+	// 每回滚一个迁移后简历表上应有的索引；SQLite 删除列时重建表，回滚需补回其余迁移的索引
+	const (
+		resumeID  = "idx_resume_model_resume_id"
+		userID    = "idx_resume_model_user_id"
+		defaults  = "idx_resume_default_user"
+		deletedAt = "idx_resume_model_deleted_at"
+		unique    = "idx_resume_resume_id"
+	)
+	steps := []struct {
+		version int
+		indexes []string // 回滚该版本之前的索引（按名称排序）
+	}{
+		{9, []string{defaults, deletedAt, userID, unique}},
+		{8, []string{defaults, deletedAt, resumeID, userID}},
+		{7, []string{defaults, deletedAt, resumeID, userID}},
+		{6, []string{defaults, deletedAt, resumeID, userID}},
+		{5, []string{defaults, deletedAt, resumeID, userID}},
+		{4, []string{defaults, deletedAt, resumeID, userID}},
+		{3, []string{defaults, resumeID, userID}},
+		{2, []string{resumeID, userID}},
+		{1, []string{resumeID, userID}},
+	}
+	for _, s := range steps {
+		if got := resumeIndexes(t, db); !reflect.DeepEqual(got, s.indexes) {
+			t.Fatalf("回滚迁移 %d 之前的索引 = %v，期望 %v", s.version, got, s.indexes)
+		}
+		if s.version > 1 {
+			var count int64
+			if err := db.Table("resume_model").Where("resume_id = ?", "r1").Count(&count).Error; err != nil || count != 1 {
+				t.Fatalf("回滚迁移 %d 之前简历数 = %d, %v", s.version, count, err)
+			}
+		}
+
+		done, err := Down(ctx, db, 1)
+		if err != nil {
+			t.Fatalf("回滚迁移 %d: %v", s.version, err)
+		}
+		if len(done) != 1 || done[0].Version != s.version {
+			t.Fatalf("回滚了 %+v，期望迁移 %d", done, s.version)
+		}
+		if err := Check(ctx, db); !errors.Is(err, ErrSchemaBehind) {
+			t.Fatalf("回滚迁移 %d 后 Check = %v，期望 ErrSchemaBehind", s.version, err)
+		}
+	}
+	if db.Migrator().HasTable("resume_model") || db.Migrator().HasTable("resume_revision_model") {
+		t.Fatal("全部回滚后仍有简历表")
+	}
+
+	// 全部回滚后可以重新执行
+	if done, err := Up(ctx, db); err != nil || len(done) != len(migrations) {
+		t.Fatalf("回滚后重新执行 Up = %d, %v", len(done), err)
+	}
+	if err := insertResume(db, "r1", "u1"); err != nil {
+		t.Fatalf("插入简历: %v", err)
+	}
+	if err := insertResume(db, "r1", "u2"); err == nil {
+		t.Fatal("简历ID重复时应违反唯一索引")
+	}
+}
+
+func TestUniqueResumeIDDuplicates(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	if _, err := Up(ctx, db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := Down(ctx, db, 1); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	before := resumeIndexes(t, db)
+
+	for _, userID := range []string{"u1", "u2"} {
+		if err := insertResume(db, "dup", userID); err != nil {
+			t.Fatalf("插入简历: %v", err)
+		}
+	}
+	done, err := Up(ctx, db)
+	if err == nil || !strings.Contains(err.Error(), "存在重复的简历ID，请先处理: dup") || len(done) != 0 {
+		t.Fatalf("有重复简历ID时 Up = %d, %v", len(done), err)
+	}
+	if err := Check(ctx, db); !errors.Is(err, ErrSchemaBehind) {
+		t.Fatalf("迁移失败后 Check = %v，期望 ErrSchemaBehind", err)
+	}
+	if got := resumeIndexes(t, db); !reflect.DeepEqual(got, before) {
+		t.Fatalf("迁移失败后索引 = %v，期望不变 %v", got, before)
+	}
+
+	// 处理重复数据后可以重新执行
+	if err := db.Exec("UPDATE resume_model SET resume_id = 'other' WHERE user_id = 'u2'").Error; err != nil {
+		t.Fatalf("UPDATE: %v", err)
+	}
+	if done, err := Up(ctx, db); err != nil || len(done) != 1 {
+		t.Fatalf("处理重复数据后 Up = %d, %v", len(done), err)
+	}
+}
+
+func TestList(t *testing.T) {
+	ctx := context.Background()
+	db := openSQLite(t)
+	if _, err := Up(ctx, db); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if _, err := Down(ctx, db, 2); err != nil {
+		t.Fatalf("Down: %v", err)
+	}
+	// 由更新的程序版本执行的迁移
+	if err := db.Create(&schemaMigration{Version: 100, Name: "future"}).Error; err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	list, err := List(ctx, db)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	var got []string
+	for _, s := range list {
+		if !s.Applied {
+			got = append(got, s.Name)
+		}
+	}
+	if want := []string{"skill_item_ids", "unique_resume_id"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("未执行的迁移 = %v，期望 %v", got, want)
+	}
+	if last := list[len(list)-1]; len(list) != len(migrations)+1 || last.Version != 100 || !last.Applied {
+		t.Errorf("List = %+v", list)
+	}
+	if _, err := Down(ctx, db, 1); err == nil {
+		t.Error("回滚不属于当前程序版本的迁移应返回错误")
+	}
+}
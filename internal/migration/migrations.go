@@ -0,0 +1,346 @@
+package migration
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// migrations 全部迁移，按版本号升序。迁移中使用当时的表结构快照，而不是 model 包中的最新模型
+var migrations = []Migration{
+	{
+		// 引入迁移之前由 AutoMigrate 创建的数据库执行时只补全缺少的列
+		Version: 1,
+		Name:    "create_resume_tables",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&resumeV1{}, &revisionV1{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&revisionV1{}, &resumeV1{})
+		},
+	},
+	{
+		Version: 2,
+		Name:    "backfill_resume_ids",
+		Up:      backfillResumeIDs,
+		// 补全的数据保留，回滚时不做任何事
+		Down: func(*gorm.DB) error { return nil },
+	},
+	{
+		// 每个用户最多一份默认简历：default_user_id 在默认简历上等于 user_id，其余为 NULL
+		Version: 3,
+		Name:    "unique_default_resume",
+		Up:      addDefaultUserID,
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&resumeV3{}, "idx_resume_default_user"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&resumeV3{}, "DefaultUserID"); err != nil {
+				return err
+			}
+			// SQLite 删除列时会重建表并丢失原有索引，按迁移 1 的结构补回
+			return tx.AutoMigrate(&resumeV1{})
+		},
+	},
+	{
+		// 删除简历改为移入回收站，回滚时回收站中的简历被永久删除
+		Version: 4,
+		Name:    "soft_delete_resumes",
+		Up: func(tx *gorm.DB) error {
+			if !tx.Migrator().HasColumn(&resumeV4{}, "DeletedAt") {
+				if err := tx.Migrator().AddColumn(&resumeV4{}, "DeletedAt"); err != nil {
+					return err
+				}
+			}
+			return tx.Migrator().CreateIndex(&resumeV4{}, "DeletedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Exec("DELETE FROM resume_model WHERE deleted_at IS NOT NULL").Error; err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropIndex(&resumeV4{}, "DeletedAt"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().DropColumn(&resumeV4{}, "DeletedAt"); err != nil {
+				return err
+			}
+			// 同迁移 3，补回 SQLite 重建表时丢失的索引
+			return restoreIndexes(tx, false)
+		},
+	},
+	{
+		// 个人简介、证书、奖项、语言、链接和出版物。已有简历的新列为 NULL，读取时视为空章节
+		Version: 5,
+		Name:    "extended_resume_sections",
+		Up: func(tx *gorm.DB) error {
+			for _, field := range resumeV5Fields {
+				if tx.Migrator().HasColumn(&resumeV5{}, field) {
+					continue
+				}
+				if err := tx.Migrator().AddColumn(&resumeV5{}, field); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, field := range resumeV5Fields {
+				if err := tx.Migrator().DropColumn(&resumeV5{}, field); err != nil {
+					return err
+				}
+			}
+			return restoreIndexes(tx, true)
+		},
+	},
+	{
+		// 用户自定义章节，已有简历的新列为 NULL
+		Version: 6,
+		Name:    "custom_resume_sections",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&resumeV6{}, "CustomSections") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&resumeV6{}, "CustomSections")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&resumeV6{}, "CustomSections"); err != nil {
+				return err
+			}
+			return restoreIndexes(tx, true)
+		},
+	},
+	{
+		// 每个用户的修订号计数，追加修订时锁定，避免同一用户并发写入不同简历时分配到相同的修订号
+		Version: 7,
+		Name:    "revision_counters",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.AutoMigrate(&revisionCounterV7{}); err != nil {
+				return err
+			}
+			return tx.Exec(`INSERT INTO resume_revision_counter_model (user_id, last_revision)
+				SELECT user_id, MAX(revision) FROM resume_revision_model
+				WHERE user_id NOT IN (SELECT user_id FROM resume_revision_counter_model)
+				GROUP BY user_id`).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&revisionCounterV7{})
+		},
+	},
+	{
+		// 技能条目的ID，已有简历的新列为 NULL，读取时按位置生成固定的ID，再次保存时写入
+		Version: 8,
+		Name:    "skill_item_ids",
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&resumeV8{}, "SkillIDs") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&resumeV8{}, "SkillIDs")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&resumeV8{}, "SkillIDs"); err != nil {
+				return err
+			}
+			return restoreIndexes(tx, true)
+		},
+	},
+	{
+		// 简历ID唯一：以唯一索引替换迁移 1 的普通索引
+		Version: 9,
+		Name:    "unique_resume_id",
+		Up:      uniqueResumeID,
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&resumeV9{}, "idx_resume_resume_id"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&resumeV1{}, "ResumeID")
+		},
+	},
+}
+
+// restoreIndexes 补回 SQLite 删除列重建表时丢失的索引：迁移 1、3 的索引，softDelete 时还有迁移 4 的索引
+func restoreIndexes(tx *gorm.DB, softDelete bool) error {
+	if err := tx.AutoMigrate(&resumeV1{}); err != nil {
+		return err
+	}
+	if !tx.Migrator().HasIndex(&resumeV3{}, "idx_resume_default_user") {
+		if err := tx.Migrator().CreateIndex(&resumeV3{}, "idx_resume_default_user"); err != nil {
+			return err
+		}
+	}
+	if softDelete && !tx.Migrator().HasIndex(&resumeV4{}, "DeletedAt") {
+		return tx.Migrator().CreateIndex(&resumeV4{}, "DeletedAt")
+	}
+	return nil
+}
+
+// resumeV1 引入迁移时的简历表
+type resumeV1 struct {
+	ID         uint           `gorm:"primaryKey"`
+	ResumeID   string         `gorm:"size:36;index"`
+	UserID     string         `gorm:"not null;size:64;index"`
+	Name       string         `gorm:"size:128"`
+	IsDefault  bool           `gorm:"not null;default:false"`
+	BasicInfo  datatypes.JSON `gorm:"type:json"`
+	Education  datatypes.JSON `gorm:"type:json"`
+	Experience datatypes.JSON `gorm:"type:json"`
+	Projects   datatypes.JSON `gorm:"type:json"`
+	Skills     datatypes.JSON `gorm:"type:json"`
+	Version    int64          `gorm:"not null;default:1"`
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func (resumeV1) TableName() string { return "resume_model" }
+
+// revisionV1 引入迁移时的修订表
+type revisionV1 struct {
+	ID        uint           `gorm:"primaryKey"`
+	UserID    string         `gorm:"not null;size:64;uniqueIndex:idx_user_revision"`
+	ResumeID  string         `gorm:"size:36;index"`
+	Revision  int            `gorm:"not null;uniqueIndex:idx_user_revision"`
+	Source    string         `gorm:"not null;size:32"`
+	JobID     *string        `gorm:"size:36;uniqueIndex"`
+	Snapshot  datatypes.JSON `gorm:"type:json"`
+	CreatedAt time.Time
+}
+
+func (revisionV1) TableName() string { return "resume_revision_model" }
+
+// backfillResumeIDs 为支持多份简历之前（每个用户仅一份简历）的数据补全简历ID、名称和默认标记
+func backfillResumeIDs(tx *gorm.DB) error {
+	var legacy []resumeV1
+	if err := tx.Where("resume_id = '' OR resume_id IS NULL").Find(&legacy).Error; err != nil {
+		return err
+	}
+
+	for _, m := range legacy {
+		id := uuid.NewString()
+		if err := tx.Model(&resumeV1{}).Where("id = ?", m.ID).Updates(map[string]any{
+			"resume_id":  id,
+			"name":       "默认简历",
+			"is_default": true,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&revisionV1{}).
+			Where("user_id = ? AND (resume_id = '' OR resume_id IS NULL)", m.UserID).
+			Update("resume_id", id).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resumeV3 简历表新增的默认简历唯一索引列
+type resumeV3 struct {
+	DefaultUserID *string `gorm:"size:64;uniqueIndex:idx_resume_default_user"`
+}
+
+func (resumeV3) TableName() string { return "resume_model" }
+
+// addDefaultUserID 添加 default_user_id 列及唯一索引。
+// 此前的并发请求可能使同一用户有多份默认简历，只保留最近更新的一份
+func addDefaultUserID(tx *gorm.DB) error {
+	if !tx.Migrator().HasColumn(&resumeV3{}, "DefaultUserID") {
+		if err := tx.Migrator().AddColumn(&resumeV3{}, "DefaultUserID"); err != nil {
+			return err
+		}
+	}
+
+	var defaults []resumeV1
+	if err := tx.Select("id", "user_id").Where("is_default = ?", true).
+		Order("user_id, updated_at DESC, id DESC").Find(&defaults).Error; err != nil {
+		return err
+	}
+	for i, m := range defaults {
+		if i == 0 || defaults[i-1].UserID != m.UserID {
+			continue
+		}
+		if err := tx.Model(&resumeV1{}).Where("id = ?", m.ID).
+			Updates(map[string]any{"is_default": false, "version": gorm.Expr("version + 1")}).Error; err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Exec("UPDATE resume_model SET default_user_id = user_id WHERE is_default = ?", true).Error; err != nil {
+		return err
+	}
+	return tx.Migrator().CreateIndex(&resumeV3{}, "idx_resume_default_user")
+}
+
+// resumeV4 简历表新增的软删除列
+type resumeV4 struct {
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+}
+
+func (resumeV4) TableName() string { return "resume_model" }
+
+// resumeV5 简历表新增的章节列
+type resumeV5 struct {
+	Summary        string         `gorm:"type:text"`
+	Certifications datatypes.JSON `gorm:"type:json"`
+	Awards         datatypes.JSON `gorm:"type:json"`
+	Languages      datatypes.JSON `gorm:"type:json"`
+	Links          datatypes.JSON `gorm:"type:json"`
+	Publications   datatypes.JSON `gorm:"type:json"`
+}
+
+func (resumeV5) TableName() string { return "resume_model" }
+
+var resumeV5Fields = []string{"Summary", "Certifications", "Awards", "Languages", "Links", "Publications"}
+
+// resumeV6 简历表新增的自定义章节列
+type resumeV6 struct {
+	CustomSections datatypes.JSON `gorm:"type:json"`
+}
+
+func (resumeV6) TableName() string { return "resume_model" }
+
+// revisionCounterV7 每个用户最近分配的修订号
+type revisionCounterV7 struct {
+	UserID       string `gorm:"primaryKey;size:64"`
+	LastRevision int    `gorm:"not null;default:0"`
+}
+
+func (revisionCounterV7) TableName() string { return "resume_revision_counter_model" }
+
+// resumeV8 简历表新增的技能ID列
+type resumeV8 struct {
+	SkillIDs datatypes.JSON `gorm:"type:json"`
+}
+
+func (resumeV8) TableName() string { return "resume_model" }
+
+// uniqueResumeID 创建简历ID的唯一索引。已有重复的简历ID时不做修改并返回错误，
+// 修订历史按简历ID关联，无法自动判断该保留哪一份，需人工处理后重新执行
+func uniqueResumeID(tx *gorm.DB) error {
+	var duplicates []string
+	if err := tx.Model(&resumeV1{}).Select("resume_id").Group("resume_id").
+		Having("COUNT(*) > 1").Limit(10).Pluck("resume_id", &duplicates).Error; err != nil {
+		return err
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("存在重复的简历ID，请先处理: %s", strings.Join(duplicates, ", "))
+	}
+
+	if tx.Migrator().HasIndex(&resumeV1{}, "ResumeID") {
+		if err := tx.Migrator().DropIndex(&resumeV1{}, "ResumeID"); err != nil {
+			return err
+		}
+	}
+	if tx.Migrator().HasIndex(&resumeV9{}, "idx_resume_resume_id") {
+		return nil
+	}
+	return tx.Migrator().CreateIndex(&resumeV9{}, "idx_resume_resume_id")
+}
+
+// resumeV9 简历ID的唯一索引
+type resumeV9 struct {
+	ResumeID string `gorm:"size:36;uniqueIndex:idx_resume_resume_id"`
+}
+
+func (resumeV9) TableName() string { return "resume_model" }
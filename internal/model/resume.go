@@ -2,17 +2,32 @@ package model
 
 import (
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 	"time"
 )
 
 type ResumeModel struct {
-	ID         uint           `gorm:"primaryKey"`
-	UserID     string         `gorm:"not null"`
-	BasicInfo  datatypes.JSON `gorm:"type:json"`
-	Education  datatypes.JSON `gorm:"type:json"`
-	Experience datatypes.JSON `gorm:"type:json"`
-	Projects   datatypes.JSON `gorm:"type:json"`
-	Skills     datatypes.JSON `gorm:"type:json"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ID             uint           `gorm:"primaryKey"`
+	ResumeID       string         `gorm:"size:36;uniqueIndex:idx_resume_resume_id"`
+	UserID         string         `gorm:"not null;size:64;index"`
+	Name           string         `gorm:"size:128"`
+	IsDefault      bool           `gorm:"not null;default:false"`
+	DefaultUserID  *string        `gorm:"size:64;uniqueIndex:idx_resume_default_user"` // 默认简历上等于 UserID，其余为 NULL，保证每个用户最多一份默认简历
+	BasicInfo      datatypes.JSON `gorm:"type:json"`
+	Education      datatypes.JSON `gorm:"type:json"`
+	Experience     datatypes.JSON `gorm:"type:json"`
+	Projects       datatypes.JSON `gorm:"type:json"`
+	Skills         datatypes.JSON `gorm:"type:json"`
+	Summary        string         `gorm:"type:text"` // 个人简介；它与以下章节在迁移 5 中加入，更早保存的简历中为 NULL
+	Certifications datatypes.JSON `gorm:"type:json"`
+	Awards         datatypes.JSON `gorm:"type:json"`
+	Languages      datatypes.JSON `gorm:"type:json"`
+	Links          datatypes.JSON `gorm:"type:json"`
+	Publications   datatypes.JSON `gorm:"type:json"`
+	CustomSections datatypes.JSON `gorm:"type:json"`          // 用户自定义章节，迁移 6 中加入，更早保存的简历中为 NULL
+	SkillIDs       datatypes.JSON `gorm:"type:json"`          // 技能条目的ID，迁移 8 中加入，更早保存的简历中为 NULL
+	Version        int64          `gorm:"not null;default:1"` // 每次修改加一，用于乐观锁
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeletedAt      gorm.DeletedAt `gorm:"index"` // 移入回收站的时间，查询时自动排除回收站中的简历
 }
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"gorm.io/datatypes"
+	"time"
+)
+
+// ResumeRevisionModel 简历的不可变修订记录，每次保存都会追加一条
+type ResumeRevisionModel struct {
+	ID        uint           `gorm:"primaryKey"`
+	UserID    string         `gorm:"not null;size:64;uniqueIndex:idx_user_revision"`
+	ResumeID  string         `gorm:"size:36;index"`
+	Revision  int            `gorm:"not null;uniqueIndex:idx_user_revision"`
+	Source    string         `gorm:"not null;size:32"`
+	JobID     *string        `gorm:"size:36;uniqueIndex"` // 由异步任务写入时的任务ID，保证同一任务只写入一次
+	Snapshot  datatypes.JSON `gorm:"type:json"`
+	CreatedAt time.Time
+}
+
+// ResumeRevisionCounterModel 每个用户最近分配的修订号。
+// 追加修订时锁定该行，使同一用户的并发写入（即使是不同简历）依次分配修订号
+type ResumeRevisionCounterModel struct {
+	UserID       string `gorm:"primaryKey;size:64"`
+	LastRevision int    `gorm:"not null;default:0"`
+}
@@ -0,0 +1,159 @@
+package resilience
+
+import (
+	"ResumeBuilder/internal/config"
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Client 对同一上游的调用统一做重试和熔断，同一上游应共用一个 Client
+type Client struct {
+	name    string
+	cfg     config.ResilienceConfig
+	breaker *breaker
+}
+
+// New 创建名为 name 的上游的 Client
+func New(name string, cfg config.ResilienceConfig) *Client {
+	return &Client{
+		name: name,
+		cfg:  cfg,
+		breaker: &breaker{
+			name:      name,
+			threshold: cfg.BreakerThreshold,
+			cooldown:  cfg.BreakerCooldown,
+		},
+	}
+}
+
+// Do 执行 fn。返回临时性 *Error（限流、5xx、网络错误）时按指数退避加随机抖动重试，
+// 上游给出 Retry-After 时按其等待；等待时间超过 MaxDelay 或超出 ctx 期限时直接返回错误。
+// 连续失败达到阈值后熔断，熔断期间返回 ErrCircuitOpen
+func (c *Client) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	for attempt := 1; ; attempt++ {
+		if wait, ok := c.breaker.allow(time.Now()); !ok {
+			return &Error{Upstream: c.name, RetryAfter: wait, Err: ErrCircuitOpen}
+		}
+
+		err := fn(ctx)
+		c.breaker.record(err, time.Now())
+		if err == nil {
+			return nil
+		}
+
+		var upErr *Error
+		var permanent *permanentError
+		if errors.As(err, &permanent) || !errors.As(err, &upErr) || !upErr.Temporary() ||
+			attempt >= c.cfg.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+
+		delay := c.backoff(attempt)
+		if upErr.RetryAfter > 0 {
+			delay = upErr.RetryAfter
+		}
+		if delay > c.cfg.MaxDelay {
+			// 需要等待太久（如 GitHub 限流到下一个小时），交给调用方提示用户稍后重试
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		log.Printf("⚠️ 调用 %s 失败（第 %d 次），%s 后重试: %v\n", c.name, attempt, delay.Round(time.Millisecond), err)
+		t := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+}
+
+// backoff 第 attempt 次失败后的等待时间：BaseDelay 逐次翻倍，不超过 MaxDelay，并在 [d/2, d] 内随机抖动
+func (c *Client) backoff(attempt int) time.Duration {
+	d := c.cfg.BaseDelay << (attempt - 1)
+	if d <= 0 || d > c.cfg.MaxDelay {
+		d = c.cfg.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + rand.N(d/2+1)
+}
+
+// breaker 熔断器：连续失败 threshold 次后打开，cooldown 后放行一个试探请求，
+// 试探成功则恢复，失败则再次打开。threshold 为 0 时不熔断
+type breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// allow 判断是否放行请求，不放行时返回建议的等待时间
+func (b *breaker) allow(now time.Time) (time.Duration, bool) {
+	if b.threshold <= 0 {
+		return 0, true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Before(b.openUntil) {
+		return b.openUntil.Sub(now), false
+	}
+	if b.failures >= b.threshold {
+		// 半开：只放行一个试探请求
+		if b.probing {
+			return b.cooldown, false
+		}
+		b.probing = true
+	}
+	return 0, true
+}
+
+// record 记录调用结果。只有上游不可用（5xx、网络错误）计为失败，限流和业务错误不影响熔断
+func (b *breaker) record(err error, now time.Time) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var upErr *Error
+	failed := errors.As(err, &upErr) && upErr.Unavailable() && !errors.Is(err, ErrCircuitOpen)
+	switch {
+	case err == nil:
+		b.failures = 0
+	case failed:
+		b.failures++
+		if b.probing || b.failures >= b.threshold {
+			b.openUntil = now.Add(b.cooldown)
+			log.Printf("⚠️ %s 连续失败 %d 次，熔断 %s\n", b.name, b.failures, b.cooldown)
+		}
+	}
+	b.probing = false
+}
+
+// IsRateLimited err 是否为上游限流
+func IsRateLimited(err error) bool {
+	var upErr *Error
+	return errors.As(err, &upErr) && upErr.RateLimited
+}
+
+// IsUnavailable err 是否为上游暂时不可用
+func IsUnavailable(err error) bool {
+	var upErr *Error
+	return errors.As(err, &upErr) && upErr.Unavailable()
+}
@@ -0,0 +1,150 @@
+package resilience
+
+import (
+	"ResumeBuilder/internal/config"
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+var (
+	errUnavailable = &Error{Upstream: "test", StatusCode: http.StatusBadGateway, Err: errors.New("502")}
+	errRateLimited = &Error{Upstream: "test", StatusCode: http.StatusTooManyRequests, RateLimited: true, Err: errors.New("429")}
+	errBadRequest  = &Error{Upstream: "test", StatusCode: http.StatusBadRequest, Err: errors.New("400")}
+	errLongWait    = &Error{Upstream: "test", RateLimited: true, RetryAfter: time.Hour, Err: errors.New("429")}
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     time.Duration
+		max      time.Duration
+		attempt  int
+		min, cap time.Duration // 等待时间应在 [min, cap] 内
+	}{
+		{"首次重试", 100 * time.Millisecond, 10 * time.Second, 1, 50 * time.Millisecond, 100 * time.Millisecond},
+		{"逐次翻倍", 100 * time.Millisecond, 10 * time.Second, 3, 200 * time.Millisecond, 400 * time.Millisecond},
+		{"不超过上限", 100 * time.Millisecond, time.Second, 5, 500 * time.Millisecond, time.Second},
+		{"移位溢出时取上限", time.Second, 10 * time.Second, 80, 5 * time.Second, 10 * time.Second},
+		{"没有等待时间", 0, 0, 1, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New("test", config.ResilienceConfig{BaseDelay: tt.base, MaxDelay: tt.max})
+			for range 100 {
+				if d := c.backoff(tt.attempt); d < tt.min || d > tt.cap {
+					t.Fatalf("backoff(%d) = %v，期望在 [%v, %v] 内", tt.attempt, d, tt.min, tt.cap)
+				}
+			}
+		})
+	}
+}
+
+func TestBreakerHalfOpen(t *testing.T) {
+	b := &breaker{name: "test", threshold: 2, cooldown: 10 * time.Second}
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+
+	// 每一步记录一次调用结果（record 为 true，err 为 nil 表示成功），或检查是否放行
+	steps := []struct {
+		name   string
+		at     time.Duration
+		record bool
+		err    error
+		allow  bool
+		wait   time.Duration
+	}{
+		{name: "连续失败未达阈值", at: 0, record: true, err: errUnavailable},
+		{name: "仍然放行", at: 0, allow: true},
+		{name: "限流不计为失败", at: 0, record: true, err: errRateLimited},
+		{name: "业务错误不计为失败", at: 0, record: true, err: errBadRequest},
+		{name: "达到阈值后熔断", at: time.Second, record: true, err: errUnavailable},
+		{name: "熔断期间拒绝", at: 5 * time.Second, wait: 6 * time.Second},
+		{name: "冷却后放行一个试探请求", at: 11 * time.Second, allow: true},
+		{name: "试探期间拒绝其他请求", at: 11 * time.Second, wait: 10 * time.Second},
+		{name: "试探失败再次熔断", at: 12 * time.Second, record: true, err: errUnavailable},
+		{name: "再次熔断期间拒绝", at: 20 * time.Second, wait: 2 * time.Second},
+		{name: "再次冷却后试探", at: 22 * time.Second, allow: true},
+		{name: "试探成功", at: 22 * time.Second, record: true},
+		{name: "恢复后全部放行", at: 22 * time.Second, allow: true},
+		{name: "恢复后不再只放行一个", at: 22 * time.Second, allow: true},
+		{name: "恢复后重新计数", at: 23 * time.Second, record: true, err: errUnavailable},
+		{name: "未达阈值", at: 23 * time.Second, allow: true},
+	}
+	for _, s := range steps {
+		if s.record {
+			b.record(s.err, at(s.at))
+			continue
+		}
+		wait, allowed := b.allow(at(s.at))
+		if allowed != s.allow || wait != s.wait {
+			t.Fatalf("%s: allow = %v, %v，期望 %v, %v", s.name, wait, allowed, s.wait, s.allow)
+		}
+	}
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := &breaker{name: "test", cooldown: time.Second}
+	now := time.Now()
+	for range 10 {
+		b.record(errUnavailable, now)
+	}
+	if _, ok := b.allow(now); !ok {
+		t.Error("threshold 为 0 时不应熔断")
+	}
+}
+
+func TestDo(t *testing.T) {
+	cfg := config.ResilienceConfig{
+		MaxAttempts:      3,
+		BaseDelay:        time.Millisecond,
+		MaxDelay:         10 * time.Millisecond,
+		BreakerThreshold: 0,
+	}
+	tests := []struct {
+		name     string
+		errs     []error // 依次返回的错误，用完后返回 nil
+		attempts int
+		wantErr  error
+	}{
+		{"成功", nil, 1, nil},
+		{"临时错误后成功", []error{errUnavailable, errRateLimited}, 3, nil},
+		{"达到最多尝试次数", []error{errUnavailable, errUnavailable, errUnavailable, errUnavailable}, 3, errUnavailable},
+		{"业务错误不重试", []error{errBadRequest}, 1, errBadRequest},
+		{"非上游错误不重试", []error{context.Canceled}, 1, context.Canceled},
+		{"Permanent 不重试", []error{Permanent(errUnavailable)}, 1, errUnavailable},
+		{"等待时间超过上限不重试", []error{errLongWait}, 1, errLongWait},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attempts := 0
+			err := New("test", cfg).Do(context.Background(), func(context.Context) error {
+				attempts++
+				if attempts <= len(tt.errs) {
+					return tt.errs[attempts-1]
+				}
+				return nil
+			})
+			if attempts != tt.attempts {
+				t.Errorf("尝试了 %d 次，期望 %d 次", attempts, tt.attempts)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Do = %v，期望 %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDoCircuitOpen(t *testing.T) {
+	c := New("test", config.ResilienceConfig{MaxAttempts: 1, BreakerThreshold: 1, BreakerCooldown: time.Minute})
+	_ = c.Do(context.Background(), func(context.Context) error { return errUnavailable })
+
+	called := false
+	err := c.Do(context.Background(), func(context.Context) error { called = true; return nil })
+	var upErr *Error
+	if called || !errors.Is(err, ErrCircuitOpen) || !errors.As(err, &upErr) || upErr.RetryAfter <= 0 || !IsUnavailable(err) {
+		t.Errorf("熔断期间 Do = %v, called = %v", err, called)
+	}
+}
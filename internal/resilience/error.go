@@ -0,0 +1,113 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// ErrCircuitOpen 上游连续失败，熔断期间直接拒绝请求
+var ErrCircuitOpen = errors.New("熔断中")
+
+// Error 调用上游服务（大模型、GitHub）失败
+type Error struct {
+	Upstream    string        // 上游名称，如 ark、openai、github
+	StatusCode  int           // 上游返回的HTTP状态码，未收到响应时为 0
+	RateLimited bool          // 被上游限流
+	RetryAfter  time.Duration // 上游要求（或熔断器建议）的等待时间，未知时为 0
+	Err         error
+}
+
+func (e *Error) Error() string {
+	if errors.Is(e.Err, ErrCircuitOpen) {
+		return fmt.Sprintf("%s 服务暂时不可用，请稍后重试", e.Upstream)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Temporary 是否为可重试的临时错误：限流、超时、5xx 或网络错误
+func (e *Error) Temporary() bool {
+	switch {
+	case errors.Is(e.Err, ErrCircuitOpen):
+		return false
+	case e.RateLimited:
+		return true
+	case e.StatusCode == 0:
+		// 调用方取消或超时不属于上游故障
+		return !errors.Is(e.Err, context.Canceled) && !errors.Is(e.Err, context.DeadlineExceeded)
+	}
+	return e.StatusCode == http.StatusRequestTimeout || e.StatusCode >= 500
+}
+
+// Unavailable 上游暂时不可用：熔断中，或重试后仍为非限流的临时错误
+func (e *Error) Unavailable() bool {
+	return errors.Is(e.Err, ErrCircuitOpen) || (!e.RateLimited && e.Temporary())
+}
+
+// NewHTTPError 根据上游的非成功响应创建错误，解析 Retry-After 与 GitHub 的限流响应头
+func NewHTTPError(upstream string, resp *http.Response, err error) *Error {
+	e := &Error{
+		Upstream:   upstream,
+		StatusCode: resp.StatusCode,
+		RetryAfter: retryAfter(resp.Header, time.Now()),
+		Err:        err,
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		e.RateLimited = true
+	case resp.StatusCode == http.StatusForbidden:
+		// GitHub 限流返回 403：主限流带 X-RateLimit-Remaining: 0，次级限流带 Retry-After
+		e.RateLimited = resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
+	}
+	return e
+}
+
+// NewNetworkError 请求未收到响应（连接失败、读超时等）
+func NewNetworkError(upstream string, err error) *Error {
+	return &Error{Upstream: upstream, Err: err}
+}
+
+// retryAfter 解析等待时间：优先 Retry-After（秒数或HTTP日期），其次 GitHub 的 X-RateLimit-Reset
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	if v := h.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+			return time.Duration(secs) * time.Second
+		}
+		if t, err := http.ParseTime(v); err == nil && t.After(now) {
+			return t.Sub(now)
+		}
+	}
+
+	if h.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(h.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			if d := time.Unix(reset, 0).Sub(now); d > 0 {
+				return d
+			}
+		}
+	}
+	return 0
+}
+
+// permanentError 标记不应再重试的错误
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent 包装 err 使 Client.Do 不再重试，errors.As 仍能取到内部的 *Error
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
@@ -1,19 +1,32 @@
 package route
 
 import (
+	"ResumeBuilder/internal/config"
 	"ResumeBuilder/internal/controller"
+	"context"
+	"expvar"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
-func Run(resumeController *controller.ResumeController) *gin.Engine {
+// aiRoutes 调用大模型的接口，使用 AITimeout 作为处理时限
+var aiRoutes = map[string]bool{
+	"POST /api/resume/:userID/generate":                true,
+	"POST /api/resume/:userID/generate/github":         true,
+	"POST /api/resume/:userID/generate/github/preview": true,
+	"POST /api/resume/:userID/generate/stream":         true,
+	"POST /api/resume/:userID/generate/github/stream":  true,
+}
+
+func Run(cfg config.HTTPConfig, resumeController *controller.ResumeController, jobController *controller.JobController) *gin.Engine {
 	r := gin.Default()
 
 	// CORS中间件 - 允许跨域请求
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, If-Match, "+controller.RequestIDHeader)
+		c.Writer.Header().Set("Access-Control-Expose-Headers", controller.RequestIDHeader+", Retry-After, ETag")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(http.StatusNoContent)
@@ -24,13 +37,59 @@ func Run(resumeController *controller.ResumeController) *gin.Engine {
 	})
 
 	// API路由 - 必须在静态文件之前定义
-	api := r.Group("/api")
+	// 处理器出错时调用 c.Error 后返回，由 ErrorHandler 统一输出带错误码和请求ID的错误响应
+	api := r.Group("/api", controller.RequestID(), controller.ErrorHandler(), deadline(cfg))
+	// 手动修改简历内容的接口按配置要求携带 If-Match；AI生成和GitHub导入由服务端在冲突时自动重试
+	ifMatch := controller.RequireIfMatch(cfg.RequireIfMatch)
 	{
 		api.GET("/resume/:userID", resumeController.GetResumeHandler)
-		api.POST("/resume", resumeController.SaveResumeHandler)
+		api.POST("/resume", ifMatch, resumeController.SaveResumeHandler)
 		api.POST("/resume/:userID/generate", resumeController.GenerateResumeHandler)
 		api.DELETE("/resume/:userID", resumeController.DeleteResumeHandler)
+		// 回收站：删除的简历保留一段时间，期间可以恢复
+		api.GET("/resume/:userID/trash", resumeController.ListTrashHandler)
+		api.POST("/resume/:userID/trash/:resumeID/restore", resumeController.RestoreResumeHandler)
 		api.POST("/resume/:userID/generate/github", resumeController.AddGitHubProjectHandler)
+		api.POST("/resume/:userID/generate/github/preview", resumeController.PreviewGitHubProjectHandler)
+		api.POST("/resume/:userID/generate/github/confirm", resumeController.ConfirmGitHubProjectHandler)
+
+		// SSE 流式接口：推送阶段变化、模型输出片段和最终结果
+		api.POST("/resume/:userID/generate/stream", resumeController.GenerateResumeStreamHandler)
+		api.POST("/resume/:userID/generate/github/stream", resumeController.AddGitHubProjectStreamHandler)
+
+		// 修订历史
+		api.GET("/resume/:userID/revisions", resumeController.ListRevisionsHandler)
+		api.GET("/resume/:userID/revisions/:revision", resumeController.GetRevisionHandler)
+		api.POST("/resume/:userID/revisions/:revision/restore", resumeController.RestoreRevisionHandler)
+		api.GET("/resume/:userID/diff", resumeController.DiffRevisionsHandler)
+
+		// 局部修改：JSON Merge Patch 以及按章节、条目编辑，/resume/:userID 下作用于默认简历
+		sections(api, "/resume/:userID", ifMatch, resumeController)
+		sections(api, "/users/:userID/resumes/:resumeID", ifMatch, resumeController)
+
+		// 多份简历管理，上面的 /resume/:userID 路由作用于默认简历
+		api.GET("/users/:userID/resumes", resumeController.ListResumesHandler)
+		api.POST("/users/:userID/resumes", resumeController.CreateResumeHandler)
+		api.GET("/users/:userID/resumes/:resumeID", resumeController.GetResumeByIDHandler)
+		api.PUT("/users/:userID/resumes/:resumeID", ifMatch, resumeController.UpdateResumeByIDHandler)
+		api.DELETE("/users/:userID/resumes/:resumeID", resumeController.DeleteResumeByIDHandler)
+		api.POST("/users/:userID/resumes/:resumeID/clone", resumeController.CloneResumeHandler)
+		api.PUT("/users/:userID/resumes/:resumeID/name", resumeController.RenameResumeHandler)
+		api.PUT("/users/:userID/resumes/:resumeID/default", resumeController.SetDefaultResumeHandler)
+		api.GET("/users/:userID/resumes/:resumeID/revisions", resumeController.ListRevisionsHandler)
+
+		// 异步任务：提交后返回任务ID，通过 /jobs/:id 轮询状态和结果；未启用任务队列时 jobController 为 nil
+		if jobController != nil {
+			api.POST("/resume/:userID/jobs/generate", jobController.SubmitGenerateJobHandler)
+			api.POST("/resume/:userID/jobs/github", jobController.SubmitGitHubJobHandler)
+			api.GET("/jobs/:id", jobController.GetJobHandler)
+			api.DELETE("/jobs/:id", jobController.CancelJobHandler)
+		}
+	}
+
+	// 运行指标：只输出简历缓存的命中、未命中、加载和错误次数，不暴露命令行参数和内存统计
+	if cfg.DebugVars {
+		r.GET("/debug/vars", debugVars)
 	}
 
 	// 静态文件服务 - 提供前端页面（放在最后，作为兜底路由）
@@ -38,13 +97,48 @@ func Run(resumeController *controller.ResumeController) *gin.Engine {
 		// 如果请求的是文件（有扩展名），则从web目录提供
 		path := c.Request.URL.Path
 		if path == "/" || path == "/index.html" {
-			c.File("./web/index.html")
+			c.File(cfg.WebDir + "/index.html")
 			return
 		}
 		// 尝试从web目录提供静态文件
-		filePath := "./web" + path
+		filePath := cfg.WebDir + path
 		c.File(filePath)
 	})
 
 	return r
 }
+
+// debugVars 以 expvar 的格式输出 resume_cache 指标
+func debugVars(c *gin.Context) {
+	stats := expvar.Get("resume_cache")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(`{"resume_cache": `+stats.String()+"}"))
+}
+
+// sections 在 prefix 指向的简历下注册局部修改接口
+func sections(api *gin.RouterGroup, prefix string, ifMatch gin.HandlerFunc, resumeController *controller.ResumeController) {
+	api.PATCH(prefix, ifMatch, resumeController.PatchResumeHandler)
+	api.POST(prefix+"/sections/:section", ifMatch, resumeController.AddItemHandler)
+	api.PUT(prefix+"/sections/:section/order", ifMatch, resumeController.ReorderItemsHandler)
+	api.PATCH(prefix+"/sections/:section/:itemID", ifMatch, resumeController.UpdateItemHandler)
+	api.DELETE(prefix+"/sections/:section/:itemID", ifMatch, resumeController.DeleteItemHandler)
+}
+
+// deadline 为请求设置处理时限。请求超时或客户端断开时 ctx 结束，
+// 下游的模型调用、GitHub请求、数据库和Redis操作随之中止
+func deadline(cfg config.HTTPConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		timeout, ok := cfg.Timeouts[route]
+		if !ok {
+			timeout = cfg.RequestTimeout
+			if aiRoutes[route] {
+				timeout = cfg.AITimeout
+			}
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
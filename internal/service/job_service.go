@@ -0,0 +1,145 @@
+package service
+
+import (
+	"ResumeBuilder/internal/dao"
+	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/job"
+	"context"
+	"encoding/json"
+	"errors"
+)
+
+// 异步任务类型
+const (
+	JobGenerate = "generate" // AI生成简历
+	JobGitHub   = "github"   // 分析GitHub项目并加入简历
+)
+
+// GenerateResult 生成任务的结果，与同步生成接口的非 replace 模式响应一致
+type GenerateResult struct {
+	Mode   domain.MergeMode    `json:"mode"`
+	Saved  bool                `json:"saved"`
+	Resume *domain.Resume      `json:"resume"`
+	Report *domain.MergeReport `json:"report,omitempty"`
+}
+
+// JobService 以异步任务方式执行耗时的AI生成和GitHub分析
+type JobService interface {
+	SubmitGenerate(ctx context.Context, userID, raw string, mode domain.MergeMode) (*job.Job, error)
+	SubmitGitHub(ctx context.Context, userID, repoURL string) (*job.Job, error)
+	GetJob(ctx context.Context, id string) (*job.Job, error)
+	CancelJob(ctx context.Context, id string) (*job.Job, error)
+}
+
+type jobService struct {
+	queue   *job.Queue
+	resumes ResumeService
+	dao     dao.ResumeDAO
+}
+
+type generatePayload struct {
+	Raw  string           `json:"raw"`
+	Mode domain.MergeMode `json:"mode"`
+}
+
+type githubPayload struct {
+	RepoURL string `json:"repo_url"`
+}
+
+// NewJobService 创建任务服务并向队列注册任务处理函数
+func NewJobService(queue *job.Queue, resumes ResumeService, dao dao.ResumeDAO) JobService {
+	s := &jobService{
+		queue:   queue,
+		resumes: resumes,
+		dao:     dao,
+	}
+	queue.Register(JobGenerate, s.runGenerate)
+	queue.Register(JobGitHub, s.runGitHub)
+	return s
+}
+
+func (s *jobService) SubmitGenerate(ctx context.Context, userID, raw string, mode domain.MergeMode) (*job.Job, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	if raw == "" {
+		return nil, domain.Invalid("raw", "raw text cannot be empty")
+	}
+	mode, err := domain.ParseMergeMode(string(mode))
+	if err != nil {
+		return nil, err
+	}
+	return s.queue.Enqueue(ctx, JobGenerate, userID, generatePayload{Raw: raw, Mode: mode})
+}
+
+func (s *jobService) SubmitGitHub(ctx context.Context, userID, repoURL string) (*job.Job, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	if repoURL == "" {
+		return nil, domain.Invalid("repo_url", "仓库地址不能为空")
+	}
+	return s.queue.Enqueue(ctx, JobGitHub, userID, githubPayload{RepoURL: repoURL})
+}
+
+func (s *jobService) GetJob(ctx context.Context, id string) (*job.Job, error) {
+	return s.queue.Get(ctx, id)
+}
+
+func (s *jobService) CancelJob(ctx context.Context, id string) (*job.Job, error) {
+	return s.queue.Cancel(ctx, id)
+}
+
+func (s *jobService) runGenerate(ctx context.Context, j *job.Job) (any, error) {
+	var p generatePayload
+	if err := json.Unmarshal(j.Payload, &p); err != nil {
+		return nil, err
+	}
+
+	// 之前的执行已写入简历（写入后任务状态未能更新），直接返回已写入的结果
+	if saved, err := s.appliedResume(ctx, j.ID); saved != nil || err != nil {
+		return &GenerateResult{Mode: p.Mode, Saved: true, Resume: saved}, err
+	}
+
+	resume, report, err := s.resumes.GenerateResume(dao.WithJobID(ctx, j.ID), p.Raw, j.UserID, p.Mode)
+	if errors.Is(err, dao.ErrJobAlreadyApplied) {
+		saved, err := s.appliedResume(ctx, j.ID)
+		return &GenerateResult{Mode: p.Mode, Saved: true, Resume: saved}, err
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &GenerateResult{
+		Mode:   p.Mode,
+		Saved:  p.Mode != domain.MergePreview,
+		Resume: resume,
+		Report: report,
+	}, nil
+}
+
+func (s *jobService) runGitHub(ctx context.Context, j *job.Job) (any, error) {
+	var p githubPayload
+	if err := json.Unmarshal(j.Payload, &p); err != nil {
+		return nil, err
+	}
+
+	// 重试时项目可能已在上一次执行中加入简历，重复添加会被判定为重复项目
+	if saved, err := s.appliedResume(ctx, j.ID); saved != nil || err != nil {
+		return saved, err
+	}
+
+	resume, err := s.resumes.AnalyzeAndAddGitHubProject(dao.WithJobID(ctx, j.ID), j.UserID, p.RepoURL)
+	if errors.Is(err, dao.ErrJobAlreadyApplied) {
+		return s.appliedResume(ctx, j.ID)
+	}
+	return resume, err
+}
+
+// appliedResume 返回任务已写入的简历快照，任务尚未写入时返回 nil, nil
+func (s *jobService) appliedResume(ctx context.Context, jobID string) (*domain.Resume, error) {
+	rev, err := s.dao.GetRevisionByJob(ctx, jobID)
+	if err != nil || rev == nil {
+		return nil, err
+	}
+	return rev.Resume, nil
+}
@@ -4,149 +4,516 @@ import (
 	"ResumeBuilder/internal/agent"
 	"ResumeBuilder/internal/dao"
 	"ResumeBuilder/internal/domain"
+	"ResumeBuilder/internal/validation"
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 )
 
+// ResumeService 简历业务逻辑。参数 resumeID 为空时表示用户的默认简历，
+// AI生成与GitHub导入始终作用于默认简历
 type ResumeService interface {
-	GetResume(ctx context.Context, userID string) (*domain.Resume, error)
+	GetResume(ctx context.Context, userID, resumeID string) (*domain.Resume, error)
+	// SaveResume 保存简历内容，r.ID 为空时保存到默认简历；教育和工作经历按时间倒序重新排列。
+	// r.Version 不为 0 时仅当简历的当前版本与之相同才保存，否则返回 dao.ErrVersionConflict
 	SaveResume(ctx context.Context, r *domain.Resume) error
-	GenerateResume(ctx context.Context, raw string, userID string) (*domain.Resume, error)
-	DeleteResume(ctx context.Context, userID string) error
+	// GenerateResume 根据原始文本生成简历，mode 决定生成结果如何写入已有的默认简历；
+	// replace 模式不返回合并报告，preview 模式不保存
+	GenerateResume(ctx context.Context, raw string, userID string, mode domain.MergeMode) (*domain.Resume, *domain.MergeReport, error)
+	// DeleteResume 将简历移入回收站，可通过 RestoreResume 恢复，超过保留期限后被永久删除
+	DeleteResume(ctx context.Context, userID, resumeID string) error
+
+	// 以下为局部修改：在行锁下读取最新内容，修改并校验后保存，返回修改后的简历；不会重新排序条目。
+	// version 不为 0 时仅当简历的当前版本与之相同才修改，否则返回 dao.ErrVersionConflict
+	// PatchResume 按 RFC 7396（JSON Merge Patch）修改简历内容
+	PatchResume(ctx context.Context, userID, resumeID string, version int64, patch []byte) (*domain.Resume, error)
+	// AddItem 在章节的 position 处插入条目（越界时追加），同时返回新条目的ID
+	AddItem(ctx context.Context, userID, resumeID string, version int64, section string, item []byte, position int) (*domain.Resume, string, error)
+	// UpdateItem 按 RFC 7396 修改单个条目
+	UpdateItem(ctx context.Context, userID, resumeID string, version int64, section, itemID string, patch []byte) (*domain.Resume, error)
+	DeleteItem(ctx context.Context, userID, resumeID string, version int64, section, itemID string) (*domain.Resume, error)
+	// ReorderItems 按 ids 的顺序重新排列章节中的条目
+	ReorderItems(ctx context.Context, userID, resumeID string, version int64, section string, ids []string) (*domain.Resume, error)
+
 	AnalyzeAndAddGitHubProject(ctx context.Context, userID, repoURL string) (*domain.Resume, error)
+	// 以下流式版本通过 progress 报告各阶段（获取README、调用模型、校验、保存）和模型输出片段
+	GenerateResumeStream(ctx context.Context, raw string, userID string, mode domain.MergeMode, progress agent.Progress) (*domain.Resume, *domain.MergeReport, error)
+	AnalyzeAndAddGitHubProjectStream(ctx context.Context, userID, repoURL string, progress agent.Progress) (*domain.Resume, error)
+
+	// PreviewGitHubProject 分析GitHub项目但不保存，供用户确认或修改
+	PreviewGitHubProject(ctx context.Context, userID, repoURL string) (*domain.Project, error)
+	// AddProject 将（可能经用户修改的）项目追加到默认简历，会进行URL和名称重复检查
+	AddProject(ctx context.Context, userID string, project *domain.Project) (*domain.Resume, error)
+
+	ListResumes(ctx context.Context, userID string) ([]domain.ResumeSummary, error)
+	CreateResume(ctx context.Context, userID, name string, content *domain.Resume) (*domain.Resume, error)
+	CloneResume(ctx context.Context, userID, resumeID, name string) (*domain.Resume, error)
+	RenameResume(ctx context.Context, userID, resumeID, name string) error
+	SetDefaultResume(ctx context.Context, userID, resumeID string) error
+	// ListTrash 列出回收站中的简历
+	ListTrash(ctx context.Context, userID string) ([]domain.ResumeSummary, error)
+	// RestoreResume 从回收站恢复简历，返回恢复后的简历
+	RestoreResume(ctx context.Context, userID, resumeID string) (*domain.Resume, error)
+
+	ListRevisions(ctx context.Context, userID, resumeID string) ([]domain.Revision, error)
+	GetRevision(ctx context.Context, userID string, revision int) (*domain.Revision, error)
+	RestoreRevision(ctx context.Context, userID string, revision int) (*domain.Resume, error)
+	DiffRevisions(ctx context.Context, userID string, from, to int) (*domain.ResumeDiff, error)
 }
 
+// ErrDuplicateProject 要添加的项目与简历中已有项目的URL或名称重复
+var ErrDuplicateProject = errors.New("该项目已存在于简历中")
+
+// ErrConcurrentUpdate AI生成或GitHub导入保存时简历被反复并发修改，重试后仍未能保存
+var ErrConcurrentUpdate = errors.New("简历正被频繁修改，保存失败，请稍后重试")
+
 type resumeService struct {
-	dao   dao.ResumeDAO
-	agent agent.AIAgent
+	dao       dao.ResumeDAO
+	agent     agent.AIAgent
+	validator *validation.Validator
 }
 
-func NewResumeService(dao dao.ResumeDAO, agent agent.AIAgent) ResumeService {
+// NewResumeService 创建 ResumeService，保存前用 validator 校验简历内容
+func NewResumeService(dao dao.ResumeDAO, agent agent.AIAgent, validator *validation.Validator) ResumeService {
 	return &resumeService{
-		dao:   dao,
-		agent: agent,
+		dao:       dao,
+		agent:     agent,
+		validator: validator,
 	}
 }
 
-func (s *resumeService) GetResume(ctx context.Context, userID string) (*domain.Resume, error) {
+func (s *resumeService) GetResume(ctx context.Context, userID, resumeID string) (*domain.Resume, error) {
 	if userID == "" {
-		return nil, errors.New("UserID 不能为空")
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
 	}
-	return s.dao.Get(ctx, userID)
+	return s.dao.Get(ctx, userID, resumeID)
 }
 
 func (s *resumeService) SaveResume(ctx context.Context, r *domain.Resume) error {
 	if r.UserID == "" {
-		return errors.New("UserID 不能为空")
+		return domain.Invalid("user_id", "UserID 不能为空")
+	}
+	if err := s.validator.Resume(r); err != nil {
+		return err
 	}
-	return s.dao.Update(ctx, r)
+	domain.SortSections(r)
+	return s.dao.Update(ctx, r, domain.SourceManual)
+}
+
+func (s *resumeService) DeleteResume(ctx context.Context, userID, resumeID string) error {
+	if userID == "" {
+		return domain.Invalid("user_id", "UserID 不能为空")
+	}
+	return s.dao.Delete(ctx, userID, resumeID)
+}
+
+func (s *resumeService) PatchResume(ctx context.Context, userID, resumeID string, version int64, patch []byte) (*domain.Resume, error) {
+	return s.modify(ctx, userID, resumeID, version, func(r *domain.Resume) error {
+		return r.ApplyMergePatch(patch)
+	})
+}
+
+func (s *resumeService) AddItem(ctx context.Context, userID, resumeID string, version int64, section string, item []byte, position int) (*domain.Resume, string, error) {
+	var id string
+	r, err := s.modify(ctx, userID, resumeID, version, func(r *domain.Resume) (err error) {
+		id, err = r.AddItem(section, item, position)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return r, id, nil
+}
+
+func (s *resumeService) UpdateItem(ctx context.Context, userID, resumeID string, version int64, section, itemID string, patch []byte) (*domain.Resume, error) {
+	return s.modify(ctx, userID, resumeID, version, func(r *domain.Resume) error {
+		return r.PatchItem(section, itemID, patch)
+	})
+}
+
+func (s *resumeService) DeleteItem(ctx context.Context, userID, resumeID string, version int64, section, itemID string) (*domain.Resume, error) {
+	return s.modify(ctx, userID, resumeID, version, func(r *domain.Resume) error {
+		return r.DeleteItem(section, itemID)
+	})
+}
+
+func (s *resumeService) ReorderItems(ctx context.Context, userID, resumeID string, version int64, section string, ids []string) (*domain.Resume, error) {
+	return s.modify(ctx, userID, resumeID, version, func(r *domain.Resume) error {
+		return r.ReorderItems(section, ids)
+	})
 }
 
-func (s *resumeService) DeleteResume(ctx context.Context, userID string) error {
+// modify 对简历做局部修改，修改后的简历通过校验才会保存；只校验改动过的内容，未涉及的历史数据不影响修改
+func (s *resumeService) modify(ctx context.Context, userID, resumeID string, version int64, fn func(r *domain.Resume) error) (*domain.Resume, error) {
 	if userID == "" {
-		return errors.New("UserID 不能为空")
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
 	}
-	return s.dao.Delete(ctx, userID)
+	return s.dao.Modify(ctx, userID, resumeID, domain.SourcePatch, func(r *domain.Resume) error {
+		if version != 0 && r.Version != version {
+			return dao.ErrVersionConflict
+		}
+		before := r.Clone()
+		if err := fn(r); err != nil {
+			return err
+		}
+		return s.validator.Changes(before, r)
+	})
 }
 
-func (s *resumeService) GenerateResume(ctx context.Context, raw string, userID string) (*domain.Resume, error) {
+func (s *resumeService) GenerateResume(ctx context.Context, raw string, userID string, mode domain.MergeMode) (*domain.Resume, *domain.MergeReport, error) {
+	return s.GenerateResumeStream(ctx, raw, userID, mode, agent.Progress{})
+}
+
+func (s *resumeService) GenerateResumeStream(ctx context.Context, raw string, userID string, mode domain.MergeMode, progress agent.Progress) (*domain.Resume, *domain.MergeReport, error) {
 	if userID == "" {
-		return nil, errors.New("UserID 不能为空")
+		return nil, nil, domain.Invalid("user_id", "UserID 不能为空")
 	}
 	if raw == "" {
-		return nil, errors.New("raw text cannot be empty")
+		return nil, nil, domain.Invalid("raw", "raw text cannot be empty")
 	}
-
-	// 初始化AI客户端
-	aiClient, err := s.agent.InitializeClient()
-	if err != nil {
-		return nil, errors.New("AI客户端初始化失败: " + err.Error())
+	if _, err := domain.ParseMergeMode(string(mode)); err != nil {
+		return nil, nil, err
 	}
 
 	// 解析简历
-	resume, err := s.agent.ParseResume(ctx, aiClient, raw)
+	parsed, err := s.agent.ParseResumeStream(ctx, raw, progress)
 	if err != nil {
-		return nil, errors.New("简历解析失败: " + err.Error())
+		return nil, nil, fmt.Errorf("简历解析失败: %w", err)
 	}
 
-	resume.UserID = userID
+	parsed.UserID = userID
+	parsed.ID = ""
 
-	// 检查用户是否已有简历
-	existing, err := s.dao.Get(ctx, userID)
-	if err == nil && existing != nil {
-		// 用户已有简历，更新而不是创建
-		if err := s.dao.Update(ctx, resume); err != nil {
-			return nil, errors.New("简历更新失败: " + err.Error())
+	// 读取已有简历、合并并保存；保存时简历已被并发修改则基于最新内容重新合并
+	var resume *domain.Resume
+	var report *domain.MergeReport
+	err = retryOnConflict(ctx, func() error {
+		// 检查用户是否已有简历
+		existing, err := s.dao.Get(ctx, userID, "")
+		if err != nil && !errors.Is(err, dao.ErrNotFound) {
+			return err
 		}
-	} else {
-		// 用户没有简历，创建新的
-		if err := s.dao.Create(ctx, resume); err != nil {
-			return nil, errors.New("简历创建失败: " + err.Error())
+		exists := err == nil && existing != nil
+
+		resume, report = parsed, nil
+		var before *domain.Resume
+		if mode == domain.MergeMerge || mode == domain.MergePreview {
+			// 合并到已有简历：保留已有条目，仅追加新条目
+			merged, r := domain.MergeResumes(existing, parsed)
+			merged.UserID = userID
+			resume, report, before = merged, &r, existing
+		}
+		domain.SortSections(resume)
+		if mode == domain.MergePreview {
+			return nil
+		}
+		// 生成结果已在 agent 中校验，合并后仍可能超出条目数限制；原样保留的已有条目不重新校验
+		if err := s.validator.Changes(before, resume); err != nil {
+			return err
+		}
+
+		progress.Enter(agent.StageSaving)
+		if exists {
+			// 用户已有简历，更新而不是创建；仅当简历仍是读取时的版本才写入
+			resume.Version = existing.Version
+			if err := s.dao.Update(ctx, resume, domain.SourceAIGenerate); err != nil {
+				return fmt.Errorf("简历更新失败: %w", err)
+			}
+		} else {
+			// 用户没有简历，创建新的
+			if err := s.dao.Create(ctx, resume, domain.SourceAIGenerate); err != nil {
+				return fmt.Errorf("简历创建失败: %w", err)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return resume, nil
+	return resume, report, nil
+}
+
+// conflictRetries AI生成和GitHub导入保存时遇到并发修改，重新读取最新简历后再次合并保存的最多次数
+const conflictRetries = 3
+
+// retryOnConflict 执行一次“读取-修改-写入”，写入时遇到 dao.ErrVersionConflict 则整体重试，
+// 重试次数用尽后返回 ErrConcurrentUpdate。调用模型等耗时步骤应放在 fn 之外，重试只重新读取和合并
+func retryOnConflict(ctx context.Context, fn func() error) error {
+	err := fn()
+	for i := 0; i < conflictRetries && errors.Is(err, dao.ErrVersionConflict) && ctx.Err() == nil; i++ {
+		err = fn()
+	}
+	if errors.Is(err, dao.ErrVersionConflict) {
+		return fmt.Errorf("%w: %w", ErrConcurrentUpdate, err)
+	}
+	return err
 }
 
 // AnalyzeAndAddGitHubProject 分析GitHub项目并添加到用户简历的Projects中
 func (s *resumeService) AnalyzeAndAddGitHubProject(ctx context.Context, userID, repoURL string) (*domain.Resume, error) {
+	return s.AnalyzeAndAddGitHubProjectStream(ctx, userID, repoURL, agent.Progress{})
+}
+
+func (s *resumeService) AnalyzeAndAddGitHubProjectStream(ctx context.Context, userID, repoURL string, progress agent.Progress) (*domain.Resume, error) {
+	project, err := s.analyzeGitHubProject(ctx, userID, repoURL, progress)
+	if err != nil {
+		return nil, err
+	}
+
+	progress.Enter(agent.StageSaving)
+	return s.AddProject(ctx, userID, project)
+}
+
+// PreviewGitHubProject 分析GitHub项目并返回Project结构体，不写入简历
+func (s *resumeService) PreviewGitHubProject(ctx context.Context, userID, repoURL string) (*domain.Project, error) {
+	return s.analyzeGitHubProject(ctx, userID, repoURL, agent.Progress{})
+}
+
+func (s *resumeService) analyzeGitHubProject(ctx context.Context, userID, repoURL string, progress agent.Progress) (*domain.Project, error) {
 	if userID == "" {
-		return nil, errors.New("UserID 不能为空")
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
 	}
 	if repoURL == "" {
-		return nil, errors.New("仓库地址不能为空")
+		return nil, domain.Invalid("repo_url", "仓库地址不能为空")
 	}
 
-	//初始化AI客户端
-	client, err := s.agent.InitializeClient()
+	//分析项目得到Project结构体
+	project, err := s.agent.AnalyzeGitHubRepoStream(ctx, repoURL, progress)
 	if err != nil {
 		return nil, err
 	}
+	if project.URL == "" {
+		project.URL = repoURL
+	}
 
-	//分析项目得到Project结构体
-	project, err := s.agent.AnalyzeGitHubRepo(ctx, client, repoURL)
-	if err != nil {
+	return project, nil
+}
+
+// AddProject 将项目添加到用户默认简历的Projects中，用户没有简历时新建
+func (s *resumeService) AddProject(ctx context.Context, userID string, project *domain.Project) (*domain.Resume, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	if project == nil {
+		return nil, domain.Invalid("project", "项目不能为空")
+	}
+	if err := s.validator.Project("project", project); err != nil {
 		return nil, err
 	}
 
-	//获取用户现有简历
-	resume, err := s.dao.Get(ctx, userID)
-	resumeExists := err == nil
+	var resume *domain.Resume
+	err := retryOnConflict(ctx, func() error {
+		//获取用户现有简历
+		var err error
+		resume, err = s.dao.Get(ctx, userID, "")
+		if err != nil && !errors.Is(err, dao.ErrNotFound) {
+			return err
+		}
+		resumeExists := err == nil
+
+		if !resumeExists {
+			// 若用户无简历，初始化一个新简历
+			resume = &domain.Resume{UserID: userID}
+		}
 
-	if !resumeExists {
-		// 若用户无简历，初始化一个新简历
-		resume = &domain.Resume{UserID: userID}
+		if err := checkDuplicateProject(resume.Projects, project); err != nil {
+			return err
+		}
+
+		// 将项目添加到Projects列表，只校验新增的项目和条目数
+		before := *resume
+		resume.Projects = append(resume.Projects, *project)
+		if err := s.validator.Changes(&before, resume); err != nil {
+			return err
+		}
+
+		// 保存更新后的简历
+		if resumeExists {
+			// 更新现有简历，resume.Version 为读取时的版本，期间被修改则返回冲突
+			return s.dao.Update(ctx, resume, domain.SourceGitHubImport)
+		}
+		// 创建新简历
+		return s.dao.Create(ctx, resume, domain.SourceGitHubImport)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// 检查项目是否已存在（通过URL或名称）
-	normalizedURL := strings.TrimSuffix(strings.ToLower(repoURL), "/")
-	for _, p := range resume.Projects {
+	return resume, nil
+}
+
+// checkDuplicateProject 检查项目是否已存在（通过URL或名称）
+func checkDuplicateProject(projects []domain.Project, project *domain.Project) error {
+	normalizedURL := strings.TrimSuffix(strings.ToLower(project.URL), "/")
+	for _, p := range projects {
 		// 通过URL匹配（URL可能为空，需要判断）
-		if p.URL != "" && strings.TrimSuffix(strings.ToLower(p.URL), "/") == normalizedURL {
-			return nil, errors.New("该GitHub项目已存在于简历中（URL重复）")
+		if p.URL != "" && normalizedURL != "" && strings.TrimSuffix(strings.ToLower(p.URL), "/") == normalizedURL {
+			return fmt.Errorf("%w（URL重复）", ErrDuplicateProject)
 		}
 		// 通过项目名称匹配（名称相同且都非空）
 		if p.Name != "" && project.Name != "" && strings.EqualFold(p.Name, project.Name) {
-			return nil, errors.New("同名项目已存在于简历中")
+			return fmt.Errorf("%w（名称重复）", ErrDuplicateProject)
 		}
 	}
+	return nil
+}
+
+// ListResumes 列出用户的全部简历
+func (s *resumeService) ListResumes(ctx context.Context, userID string) ([]domain.ResumeSummary, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	return s.dao.List(ctx, userID)
+}
+
+// CreateResume 为用户新建一份简历，content 为空时创建空白简历
+func (s *resumeService) CreateResume(ctx context.Context, userID, name string, content *domain.Resume) (*domain.Resume, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+
+	resume := &domain.Resume{}
+	if content != nil {
+		resume = content
+	}
+	resume.ID = ""
+	resume.UserID = userID
+	resume.Name = strings.TrimSpace(name)
+	if err := s.validator.Resume(resume); err != nil {
+		return nil, err
+	}
+	domain.SortSections(resume)
+
+	if err := s.dao.Create(ctx, resume, domain.SourceManual); err != nil {
+		return nil, fmt.Errorf("简历创建失败: %w", err)
+	}
+	return resume, nil
+}
+
+// CloneResume 复制一份简历，name 为空时在原名称后追加“副本”
+func (s *resumeService) CloneResume(ctx context.Context, userID, resumeID, name string) (*domain.Resume, error) {
+	source, err := s.GetResume(ctx, userID, resumeID)
+	if err != nil {
+		return nil, err
+	}
+
+	clone := *source
+	clone.ID = ""
+	clone.Name = strings.TrimSpace(name)
+	if clone.Name == "" {
+		clone.Name = source.Name + " 副本"
+	}
 
-	// 将分析结果添加到Projects列表
-	resume.Projects = append(resume.Projects, *project)
+	if err := s.dao.Create(ctx, &clone, domain.SourceClone); err != nil {
+		return nil, fmt.Errorf("简历复制失败: %w", err)
+	}
+	return &clone, nil
+}
 
-	// 保存更新后的简历
-	if resumeExists {
-		// 更新现有简历
-		if err := s.dao.Update(ctx, resume); err != nil {
-			return nil, err
+// RenameResume 重命名简历
+func (s *resumeService) RenameResume(ctx context.Context, userID, resumeID, name string) error {
+	if userID == "" {
+		return domain.Invalid("user_id", "UserID 不能为空")
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return domain.Invalid("name", "简历名称不能为空")
+	}
+	return s.dao.Rename(ctx, userID, resumeID, name)
+}
+
+// SetDefaultResume 将指定简历设为默认简历
+func (s *resumeService) SetDefaultResume(ctx context.Context, userID, resumeID string) error {
+	if userID == "" {
+		return domain.Invalid("user_id", "UserID 不能为空")
+	}
+	return s.dao.SetDefault(ctx, userID, resumeID)
+}
+
+// ListTrash 列出回收站中的简历，最近删除的在前
+func (s *resumeService) ListTrash(ctx context.Context, userID string) ([]domain.ResumeSummary, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	return s.dao.ListTrash(ctx, userID)
+}
+
+// RestoreResume 从回收站恢复简历，用户没有其他简历时恢复的简历成为默认简历
+func (s *resumeService) RestoreResume(ctx context.Context, userID, resumeID string) (*domain.Resume, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	if err := s.dao.Restore(ctx, userID, resumeID); err != nil {
+		return nil, err
+	}
+	return s.dao.Get(ctx, userID, resumeID)
+}
+
+// ListRevisions 列出简历的全部修订
+func (s *resumeService) ListRevisions(ctx context.Context, userID, resumeID string) ([]domain.Revision, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	return s.dao.ListRevisions(ctx, userID, resumeID)
+}
+
+// GetRevision 获取指定修订的完整内容
+func (s *resumeService) GetRevision(ctx context.Context, userID string, revision int) (*domain.Revision, error) {
+	if userID == "" {
+		return nil, domain.Invalid("user_id", "UserID 不能为空")
+	}
+	return s.dao.GetRevision(ctx, userID, revision)
+}
+
+// RestoreRevision 将简历恢复为指定修订的内容，恢复本身也会产生一条新修订
+func (s *resumeService) RestoreRevision(ctx context.Context, userID string, revision int) (*domain.Resume, error) {
+	rev, err := s.GetRevision(ctx, userID, revision)
+	if err != nil {
+		return nil, err
+	}
+
+	resume := rev.Resume
+	resume.UserID = userID
+	resume.ID = rev.ResumeID
+	// 快照中的版本是当时的版本，恢复时无条件覆盖
+	resume.Version = 0
+
+	// 简历在回收站中时先恢复；已被永久删除时以原ID重新创建
+	existing, err := s.dao.Get(ctx, userID, rev.ResumeID)
+	if errors.Is(err, dao.ErrNotFound) {
+		if err = s.dao.Restore(ctx, userID, rev.ResumeID); err == nil {
+			existing, err = s.dao.Get(ctx, userID, rev.ResumeID)
+		}
+	}
+	if err != nil && !errors.Is(err, dao.ErrNotFound) {
+		return nil, err
+	}
+	if err == nil && existing != nil {
+		if err := s.dao.Update(ctx, resume, domain.SourceRestore); err != nil {
+			return nil, fmt.Errorf("简历恢复失败: %w", err)
 		}
 	} else {
-		// 创建新简历
-		if err := s.dao.Create(ctx, resume); err != nil {
-			return nil, err
+		if err := s.dao.Create(ctx, resume, domain.SourceRestore); err != nil {
+			return nil, fmt.Errorf("简历恢复失败: %w", err)
 		}
 	}
 
 	return resume, nil
 }
+
+// DiffRevisions 计算两个修订之间按章节划分的差异
+func (s *resumeService) DiffRevisions(ctx context.Context, userID string, from, to int) (*domain.ResumeDiff, error) {
+	fromRev, err := s.GetRevision(ctx, userID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRev, err := s.GetRevision(ctx, userID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := domain.DiffResumes(fromRev.Resume, toRev.Resume)
+	return &diff, nil
+}
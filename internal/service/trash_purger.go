@@ -0,0 +1,68 @@
+package service
+
+import (
+	"ResumeBuilder/internal/dao"
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// TrashPurger 定期永久删除回收站中超过保留期限的简历。
+// 多个实例同时清理是安全的，重复删除不会产生影响
+type TrashPurger struct {
+	dao       dao.ResumeDAO
+	retention time.Duration
+	interval  time.Duration
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
+}
+
+// NewTrashPurger 创建清理器，每隔 interval 删除移入回收站超过 retention 的简历
+func NewTrashPurger(dao dao.ResumeDAO, retention, interval time.Duration) *TrashPurger {
+	return &TrashPurger{dao: dao, retention: retention, interval: interval}
+}
+
+// Start 在后台启动清理，启动时立即清理一次
+func (p *TrashPurger) Start() {
+	ctx, stop := context.WithCancel(context.Background())
+	p.stop = stop
+
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		for {
+			p.purge(ctx)
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop 停止清理，中止进行中的清理并等待其退出
+func (p *TrashPurger) Stop() {
+	if p.stop == nil {
+		return
+	}
+	p.stop()
+	p.wg.Wait()
+}
+
+func (p *TrashPurger) purge(ctx context.Context) {
+	n, err := p.dao.Purge(ctx, time.Now().Add(-p.retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("⚠️ 清理回收站失败: %v\n", err)
+		}
+		return
+	}
+	if n > 0 {
+		log.Printf("🗑️ 已永久删除回收站中超过 %v 的简历 %d 份\n", p.retention, n)
+	}
+}
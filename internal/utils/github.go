@@ -1,6 +1,7 @@
 package utils
 
 import (
+	"ResumeBuilder/internal/resilience"
 	"context"
 	"encoding/base64"
 	"encoding/json"
@@ -13,6 +14,9 @@ import (
 	"time"
 )
 
+// GitHubUpstream GitHub 在重试与熔断中的上游名称
+const GitHubUpstream = "github"
+
 // httpClient 配置了超时的HTTP客户端
 var httpClient = &http.Client{
 	Timeout: 30 * time.Second,
@@ -30,7 +34,7 @@ func FetchFile(ctx context.Context, url, token string) (string, error) {
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("请求文件失败: %w", err)
+		return "", resilience.NewNetworkError(GitHubUpstream, fmt.Errorf("请求文件失败: %w", err))
 	}
 	defer resp.Body.Close()
 
@@ -38,7 +42,7 @@ func FetchFile(ctx context.Context, url, token string) (string, error) {
 		if resp.StatusCode == http.StatusNotFound {
 			return "", errors.New("文件不存在")
 		}
-		return "", fmt.Errorf("请求失败，状态码: %d", resp.StatusCode)
+		return "", resilience.NewHTTPError(GitHubUpstream, resp, fmt.Errorf("请求失败，状态码: %d", resp.StatusCode))
 	}
 
 	content, err := io.ReadAll(resp.Body)
@@ -132,6 +136,11 @@ func FetchREADME(ctx context.Context, repoURL, token string) (string, error) {
 	var lastErr error
 	for _, branch := range branches {
 		for _, readme := range readmeFiles {
+			// 请求已取消或超时，不再尝试其余分支
+			if err := ctx.Err(); err != nil {
+				return "", err
+			}
+
 			rawURL, err := ConvertToRawURL(repoURL, branch, readme)
 			if err != nil {
 				return "", fmt.Errorf("URL转换失败: %w", err)
@@ -146,6 +155,11 @@ func FetchREADME(ctx context.Context, repoURL, token string) (string, error) {
 			}
 			if err != nil {
 				fmt.Printf("✗ %v\n", err)
+				// 限流或服务故障时换分支重试没有意义，交给调用方按退避策略重试
+				var upErr *resilience.Error
+				if errors.As(err, &upErr) && upErr.Temporary() {
+					return "", err
+				}
 			} else {
 				fmt.Printf("✗ 内容为空\n")
 			}
@@ -224,7 +238,7 @@ func FetchREADMEViaAPI(ctx context.Context, repoURL, token string) (string, erro
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		fmt.Printf("  ✗ GitHub API请求失败: %v\n", err)
-		return "", fmt.Errorf("请求GitHub API失败: %w", err)
+		return "", resilience.NewNetworkError(GitHubUpstream, fmt.Errorf("请求GitHub API失败: %w", err))
 	}
 	defer resp.Body.Close()
 
@@ -238,7 +252,7 @@ func FetchREADMEViaAPI(ctx context.Context, repoURL, token string) (string, erro
 		if resp.StatusCode == 403 {
 			fmt.Printf("  ✗ API返回403: 可能是限流或需要认证\n")
 		}
-		return "", fmt.Errorf("GitHub API返回错误，状态码: %d", resp.StatusCode)
+		return "", resilience.NewHTTPError(GitHubUpstream, resp, fmt.Errorf("GitHub API返回错误，状态码: %d", resp.StatusCode))
 	}
 
 	// 解析API响应
@@ -309,12 +323,12 @@ func FetchRepoMetadata(ctx context.Context, repoURL, token string) (*GitHubRepoM
 	resp, err := httpClient.Do(req)
 	if err != nil {
 		fmt.Printf("  ✗ 请求失败: %v\n", err)
-		return nil, fmt.Errorf("请求GitHub API失败: %w", err)
+		return nil, resilience.NewNetworkError(GitHubUpstream, fmt.Errorf("请求GitHub API失败: %w", err))
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API返回错误，状态码: %d", resp.StatusCode)
+		return nil, resilience.NewHTTPError(GitHubUpstream, resp, fmt.Errorf("API返回错误，状态码: %d", resp.StatusCode))
 	}
 
 	// 解析API响应
@@ -0,0 +1,99 @@
+package validation
+
+import (
+	"ResumeBuilder/internal/domain"
+	"fmt"
+	"strings"
+)
+
+// 支持的提示语言
+const (
+	LangZH = "zh"
+	LangEN = "en"
+)
+
+// 校验规则，对应 domain.FieldError 的 Rule
+const (
+	RuleRequired  = "required"
+	RuleMaxLength = "max_length"
+	RuleMaxItems  = "max_items"
+	RuleEmail     = "email"
+	RulePhone     = "phone"
+	RuleURL       = "url"
+	RuleDate      = "date"
+	RuleDateOrder = "date_order"
+)
+
+// messages 各规则在各语言下的提示，%d 等占位符由 FieldError.Params 填充
+var messages = map[string]map[string]string{
+	RuleRequired: {
+		LangZH: "不能为空",
+		LangEN: "is required",
+	},
+	RuleMaxLength: {
+		LangZH: "长度不能超过 %d 个字符",
+		LangEN: "must be at most %d characters",
+	},
+	RuleMaxItems: {
+		LangZH: "最多 %d 项",
+		LangEN: "must contain at most %d items",
+	},
+	RuleEmail: {
+		LangZH: "邮箱格式不正确",
+		LangEN: "must be a valid email address",
+	},
+	RulePhone: {
+		LangZH: "电话号码格式不正确",
+		LangEN: "must be a valid phone number",
+	},
+	RuleURL: {
+		LangZH: "必须是 http 或 https 链接",
+		LangEN: "must be an http or https URL",
+	},
+	RuleDate: {
+		LangZH: "日期格式不正确，应为 2020、2020-09、2020-09-01 或“至今”",
+		LangEN: `must be a date such as 2020, 2020-09, 2020-09-01 or "present"`,
+	},
+	RuleDateOrder: {
+		LangZH: "结束日期不能早于开始日期",
+		LangEN: "must not be earlier than start_date",
+	},
+}
+
+// message 生成规则的提示，未知语言使用中文
+func message(rule, lang string, params []any) string {
+	texts, ok := messages[rule]
+	if !ok {
+		return rule
+	}
+	text, ok := texts[lang]
+	if !ok {
+		text = texts[LangZH]
+	}
+	if len(params) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, params...)
+}
+
+// ParseLang 根据 Accept-Language 选择提示语言，首选英文时返回 LangEN，其余返回 LangZH
+func ParseLang(acceptLanguage string) string {
+	first, _, _ := strings.Cut(acceptLanguage, ",")
+	first, _, _ = strings.Cut(first, ";")
+	if strings.HasPrefix(strings.ToLower(strings.TrimSpace(first)), "en") {
+		return LangEN
+	}
+	return LangZH
+}
+
+// Localize 返回 lang 语言的校验错误；没有 Rule 的错误（如参数校验的固定提示）保持原样
+func Localize(err *domain.ValidationError, lang string) *domain.ValidationError {
+	fields := make([]domain.FieldError, len(err.Fields))
+	for i, f := range err.Fields {
+		if f.Rule != "" {
+			f.Message = message(f.Rule, lang, f.Params)
+		}
+		fields[i] = f
+	}
+	return &domain.ValidationError{Fields: fields}
+}
@@ -0,0 +1,346 @@
+package validation
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/domain"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validator 按配置的大小限制校验简历内容，提示默认为中文
+type Validator struct {
+	cfg config.ValidationConfig
+}
+
+// New 创建 Validator
+func New(cfg config.ValidationConfig) *Validator {
+	return &Validator{cfg: cfg}
+}
+
+// Resume 校验整份简历，未通过时返回包含全部问题的 *domain.ValidationError
+func (v *Validator) Resume(r *domain.Resume) error {
+	c := &checker{cfg: v.cfg}
+	c.field("name", r.Name)
+
+	c.items("basic_info", len(r.BasicInfo), v.cfg.MaxItems)
+	for i, b := range r.BasicInfo {
+		p := index("basic_info", i)
+		c.field(p+".name", b.Name)
+		c.field(p+".title", b.Title)
+		c.field(p+".location", b.Location)
+		c.email(p+".email", b.Email)
+		c.phone(p+".phone", b.Phone)
+	}
+	c.text("summary", r.Summary)
+
+	c.items("education", len(r.Education), v.cfg.MaxItems)
+	for i, e := range r.Education {
+		p := index("education", i)
+		c.required(p+".school", e.School)
+		c.field(p+".school", e.School)
+		c.field(p+".major", e.Major)
+		c.field(p+".degree", e.Degree)
+		c.period(p, e.StartDate, e.EndDate)
+	}
+
+	c.items("experience", len(r.Experience), v.cfg.MaxItems)
+	for i, e := range r.Experience {
+		p := index("experience", i)
+		c.required(p+".company", e.Company)
+		c.field(p+".company", e.Company)
+		c.field(p+".position", e.Position)
+		c.period(p, e.StartDate, e.EndDate)
+		c.text(p+".description", e.Description)
+		c.list(p+".achievements", e.Achievements, v.cfg.MaxListItems, c.text)
+	}
+
+	c.items("projects", len(r.Projects), v.cfg.MaxItems)
+	for i := range r.Projects {
+		c.project(index("projects", i), &r.Projects[i])
+	}
+
+	c.list("skills", r.Skills, v.cfg.MaxSkills, c.field)
+
+	c.items("certifications", len(r.Certifications), v.cfg.MaxItems)
+	for i, cert := range r.Certifications {
+		p := index("certifications", i)
+		c.required(p+".name", cert.Name)
+		c.field(p+".name", cert.Name)
+		c.field(p+".issuer", cert.Issuer)
+		c.date(p+".date", cert.Date)
+		c.url(p+".url", cert.URL)
+	}
+
+	c.items("awards", len(r.Awards), v.cfg.MaxItems)
+	for i, a := range r.Awards {
+		p := index("awards", i)
+		c.required(p+".title", a.Title)
+		c.field(p+".title", a.Title)
+		c.field(p+".issuer", a.Issuer)
+		c.date(p+".date", a.Date)
+		c.text(p+".description", a.Description)
+	}
+
+	c.items("languages", len(r.Languages), v.cfg.MaxItems)
+	for i, l := range r.Languages {
+		p := index("languages", i)
+		c.required(p+".language", l.Language)
+		c.field(p+".language", l.Language)
+		c.field(p+".proficiency", l.Proficiency)
+	}
+
+	c.items("links", len(r.Links), v.cfg.MaxItems)
+	for i, l := range r.Links {
+		p := index("links", i)
+		c.field(p+".label", l.Label)
+		c.required(p+".url", l.URL)
+		c.url(p+".url", l.URL)
+	}
+
+	c.items("publications", len(r.Publications), v.cfg.MaxItems)
+	for i, pub := range r.Publications {
+		p := index("publications", i)
+		c.required(p+".title", pub.Title)
+		c.field(p+".title", pub.Title)
+		c.field(p+".publisher", pub.Publisher)
+		c.date(p+".date", pub.Date)
+		c.url(p+".url", pub.URL)
+		c.text(p+".description", pub.Description)
+	}
+
+	c.items("custom_sections", len(r.CustomSections), v.cfg.MaxItems)
+	for i, section := range r.CustomSections {
+		p := index("custom_sections", i)
+		c.required(p+".title", section.Title)
+		c.field(p+".title", section.Title)
+		c.items(p+".entries", len(section.Entries), v.cfg.MaxItems)
+		for j, e := range section.Entries {
+			ep := index(p+".entries", j)
+			c.required(ep+".title", e.Title)
+			c.field(ep+".title", e.Title)
+			c.field(ep+".subtitle", e.Subtitle)
+			c.period(ep, e.StartDate, e.EndDate)
+			c.list(ep+".bullets", e.Bullets, v.cfg.MaxListItems, c.text)
+			c.url(ep+".url", e.URL)
+		}
+	}
+	return c.err()
+}
+
+// Changes 校验局部修改后的简历，只报告新增或改动过的内容中的问题：
+// before 中原样保留的条目（如升级前保存的无法识别的日期）不会导致修改失败。before 为 nil 时等同于 Resume
+func (v *Validator) Changes(before, after *domain.Resume) error {
+	err := v.Resume(after)
+	var verr *domain.ValidationError
+	if before == nil || !errors.As(err, &verr) {
+		return err
+	}
+
+	old, cur := document(before), document(after)
+	var fields []domain.FieldError
+	for _, f := range verr.Fields {
+		if !unchanged(old, cur, f.Field) {
+			fields = append(fields, f)
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &domain.ValidationError{Fields: fields}
+}
+
+// document 简历的 JSON 形式，用于比较修改前后的内容
+func document(r *domain.Resume) map[string]any {
+	doc := map[string]any{}
+	if data, err := json.Marshal(r); err == nil {
+		_ = json.Unmarshal(data, &doc)
+	}
+	return doc
+}
+
+// unchanged 问题所在的内容在修改前就已存在：路径指向条目（如 experience[2].start_date）时，
+// 修改前有完全相同的条目即可，不要求位置相同；否则比较整个字段
+func unchanged(old, cur map[string]any, path string) bool {
+	name, _, _ := strings.Cut(path, ".")
+	section, i, indexed := parseIndex(name)
+	if !indexed {
+		return reflect.DeepEqual(old[name], cur[name])
+	}
+	items, _ := cur[section].([]any)
+	if i >= len(items) {
+		return false
+	}
+	previous, _ := old[section].([]any)
+	for _, item := range previous {
+		if reflect.DeepEqual(item, items[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseIndex 拆分 section[i] 形式的路径段
+func parseIndex(name string) (section string, i int, ok bool) {
+	section, idx, found := strings.Cut(name, "[")
+	if !found || !strings.HasSuffix(idx, "]") {
+		return name, 0, false
+	}
+	if _, err := fmt.Sscanf(idx, "%d]", &i); err != nil || i < 0 {
+		return name, 0, false
+	}
+	return section, i, true
+}
+
+// Project 校验单个项目，path 为错误路径的前缀，如 project；为空时路径从项目的字段名开始
+func (v *Validator) Project(path string, p *domain.Project) error {
+	c := &checker{cfg: v.cfg}
+	c.project(path, p)
+	return c.err()
+}
+
+// checker 收集一次校验中的全部问题
+type checker struct {
+	cfg    config.ValidationConfig
+	fields []domain.FieldError
+}
+
+func (c *checker) err() error {
+	if len(c.fields) == 0 {
+		return nil
+	}
+	return &domain.ValidationError{Fields: c.fields}
+}
+
+func (c *checker) add(path, rule string, params ...any) {
+	c.fields = append(c.fields, domain.FieldError{
+		Field:   path,
+		Rule:    rule,
+		Message: message(rule, LangZH, params),
+		Params:  params,
+	})
+}
+
+func (c *checker) project(path string, p *domain.Project) {
+	c.required(join(path, "name"), p.Name)
+	c.field(join(path, "name"), p.Name)
+	c.field(join(path, "role"), p.Role)
+	c.text(join(path, "description"), p.Description)
+	c.list(join(path, "tech_stack"), p.TechStack, c.cfg.MaxListItems, c.field)
+	c.list(join(path, "highlights"), p.Highlights, c.cfg.MaxListItems, c.text)
+	c.url(join(path, "url"), p.URL)
+}
+
+func (c *checker) required(path, s string) {
+	if strings.TrimSpace(s) == "" {
+		c.add(path, RuleRequired)
+	}
+}
+
+// field 短字段的长度限制
+func (c *checker) field(path, s string) {
+	c.length(path, s, c.cfg.MaxFieldLength)
+}
+
+// text 长文本的长度限制
+func (c *checker) text(path, s string) {
+	c.length(path, s, c.cfg.MaxTextLength)
+}
+
+func (c *checker) length(path, s string, max int) {
+	if max > 0 && utf8.RuneCountInString(s) > max {
+		c.add(path, RuleMaxLength, max)
+	}
+}
+
+func (c *checker) items(path string, n, max int) {
+	if max > 0 && n > max {
+		c.add(path, RuleMaxItems, max)
+	}
+}
+
+// list 校验列表的条数，并用 each 校验每一项；空项视为缺失
+func (c *checker) list(path string, values []string, max int, each func(path, s string)) {
+	c.items(path, len(values), max)
+	for i, s := range values {
+		p := index(path, i)
+		c.required(p, s)
+		each(p, s)
+	}
+}
+
+func (c *checker) email(path, s string) {
+	if s == "" {
+		return
+	}
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s || !strings.Contains(s[strings.LastIndex(s, "@")+1:], ".") {
+		c.add(path, RuleEmail)
+	}
+}
+
+// phoneChars 电话号码允许的字符：数字、开头的 +、空格、横线、点和括号
+var phoneChars = regexp.MustCompile(`^\+?[\d\s\-.()（）]+$`)
+
+func (c *checker) phone(path, s string) {
+	if s == "" {
+		return
+	}
+	digits := 0
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	// E.164 号码最长 15 位；短于 6 位的不是有效号码
+	if !phoneChars.MatchString(s) || digits < 6 || digits > 15 {
+		c.add(path, RulePhone)
+	}
+}
+
+func (c *checker) url(path, s string) {
+	if s == "" {
+		return
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		c.add(path, RuleURL)
+	}
+}
+
+// period 校验起止日期：必须可以识别，开始日期不能为至今，结束日期不能早于开始日期；日期可以为空
+func (c *checker) period(path string, start, end domain.PartialDate) {
+	if !start.IsZero() && (!start.Valid() || start.Ongoing) {
+		c.add(path+".start_date", RuleDate)
+	}
+	if !end.IsZero() && !end.Valid() {
+		c.add(path+".end_date", RuleDate)
+	}
+	if !start.Ongoing && end.Before(start) {
+		c.add(path+".end_date", RuleDateOrder)
+	}
+}
+
+// date 校验单个日期：必须可以识别且不能为至今；日期可以为空
+func (c *checker) date(path string, d domain.PartialDate) {
+	if !d.IsZero() && (!d.Valid() || d.Ongoing) {
+		c.add(path, RuleDate)
+	}
+}
+
+func index(path string, i int) string {
+	return fmt.Sprintf("%s[%d]", path, i)
+}
+
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
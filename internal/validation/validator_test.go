@@ -0,0 +1,262 @@
+package validation
+
+import (
+	"ResumeBuilder/internal/config"
+	"ResumeBuilder/internal/domain"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testConfig 较小的限制，便于构造超限的内容
+var testConfig = config.ValidationConfig{
+	MaxItems:       2,
+	MaxListItems:   2,
+	MaxSkills:      3,
+	MaxFieldLength: 10,
+	MaxTextLength:  20,
+}
+
+// parseResume 从 JSON 解析简历，无法识别的日期原样保留
+func parseResume(t *testing.T, s string) *domain.Resume {
+	t.Helper()
+	var r domain.Resume
+	if err := json.Unmarshal([]byte(s), &r); err != nil {
+		t.Fatalf("Unmarshal(%s): %v", s, err)
+	}
+	return &r
+}
+
+// problems 将校验错误概括为 "字段 规则" 的列表
+func problems(t *testing.T, err error) []string {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v，期望 *domain.ValidationError", err)
+	}
+	out := make([]string, len(verr.Fields))
+	for i, f := range verr.Fields {
+		out[i] = f.Field + " " + f.Rule
+	}
+	return out
+}
+
+func TestResume(t *testing.T) {
+	long := `"` + strings.Repeat("长", 11) + `"`
+	longText := `"` + strings.Repeat("长", 21) + `"`
+
+	tests := []struct {
+		name   string
+		resume string
+		want   []string
+	}{
+		{"空简历", `{}`, nil},
+		{
+			"完整且有效",
+			`{"name":"默认简历","basic_info":[{"name":"张三","email":"zs@example.com","phone":"+86 138-0000-0000"}],
+			"education":[{"school":"北京大学","start_date":"2015-09","end_date":"2019-06"}],
+			"experience":[{"company":"字节跳动","start_date":"2019-07","end_date":"至今","achievements":["重构网关"]}],
+			"projects":[{"name":"简历生成器","url":"https://github.com/u/resume","tech_stack":["Go"]}],
+			"skills":["Go","MySQL","Redis"],
+			"certifications":[{"name":"CKA","date":"2021"}],
+			"links":[{"label":"博客","url":"http://blog.example.com"}]}`,
+			nil,
+		},
+		{
+			"必填字段",
+			`{"education":[{"school":" "}],"experience":[{}],"projects":[{}],"awards":[{}],"languages":[{}],"links":[{}],"publications":[{}],"certifications":[{}]}`,
+			[]string{
+				"education[0].school required", "experience[0].company required", "projects[0].name required",
+				"certifications[0].name required", "awards[0].title required", "languages[0].language required",
+				"links[0].url required", "publications[0].title required",
+			},
+		},
+		{
+			"长度限制按字符计算",
+			`{"name":` + long + `,"summary":` + longText + `,"skills":["一二三四五六七八九十"],"experience":[{"company":"字节跳动","description":` + longText + `}]}`,
+			[]string{"name max_length", "summary max_length", "experience[0].description max_length"},
+		},
+		{
+			"条目数限制",
+			`{"education":[{"school":"a"},{"school":"b"},{"school":"c"}],"skills":["a","b","c","d"],
+			"experience":[{"company":"a","achievements":["1","2","3"]}]}`,
+			[]string{"education max_items", "experience[0].achievements max_items", "skills max_items"},
+		},
+		{
+			"列表中的空项",
+			`{"skills":["Go",""],"projects":[{"name":"a","highlights":[" "]}]}`,
+			[]string{"projects[0].highlights[0] required", "skills[1] required"},
+		},
+		{
+			"联系方式格式",
+			`{"basic_info":[{"email":"张三@","phone":"12345"},{"email":"a@localhost","phone":"电话 13800000000"}]}`,
+			[]string{
+				"basic_info[0].email email", "basic_info[0].phone phone",
+				"basic_info[1].email email", "basic_info[1].phone phone",
+			},
+		},
+		{
+			"链接格式",
+			`{"projects":[{"name":"a","url":"github.com/u/a"}],"links":[{"url":"ftp://example.com"}],"certifications":[{"name":"a","url":"https://"}]}`,
+			[]string{"projects[0].url url", "certifications[0].url url", "links[0].url url"},
+		},
+		{
+			"无法识别的日期",
+			`{"education":[{"school":"a","start_date":"2019.09-2020.06","end_date":"明年"}],"awards":[{"title":"a","date":"去年"}]}`,
+			[]string{"education[0].start_date date", "education[0].end_date date", "awards[0].date date"},
+		},
+		{
+			"开始日期和单个日期不能为至今",
+			`{"experience":[{"company":"a","start_date":"至今"}],"publications":[{"title":"a","date":"至今"}]}`,
+			[]string{"experience[0].start_date date", "publications[0].date date"},
+		},
+		{
+			"结束日期早于开始日期",
+			`{"experience":[{"company":"a","start_date":"2020-09","end_date":"2020-03"},{"company":"b","start_date":"2020","end_date":"2020-03"}]}`,
+			[]string{"experience[0].end_date date_order"},
+		},
+		{
+			"自定义章节",
+			`{"custom_sections":[{"title":"","entries":[{"title":"a","start_date":"2021","end_date":"2020","url":"x"},{},{"title":"c"}]}]}`,
+			[]string{
+				"custom_sections[0].title required", "custom_sections[0].entries max_items",
+				"custom_sections[0].entries[0].end_date date_order", "custom_sections[0].entries[0].url url",
+				"custom_sections[0].entries[1].title required",
+			},
+		},
+	}
+	v := New(testConfig)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := problems(t, v.Resume(parseResume(t, tt.resume)))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Resume = %q\n期望 %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProject(t *testing.T) {
+	v := New(testConfig)
+	tests := []struct {
+		path    string
+		project domain.Project
+		want    []string
+	}{
+		{"project", domain.Project{Name: "a", TechStack: []string{"Go"}}, nil},
+		{"project", domain.Project{URL: "x"}, []string{"project.name required", "project.url url"}},
+		{"", domain.Project{Name: "a", TechStack: []string{"Go", "", "Redis"}}, []string{"tech_stack max_items", "tech_stack[1] required"}},
+	}
+	for _, tt := range tests {
+		got := problems(t, v.Project(tt.path, &tt.project))
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Project(%q, %+v) = %q，期望 %q", tt.path, tt.project, got, tt.want)
+		}
+	}
+}
+
+func TestMessages(t *testing.T) {
+	err := New(testConfig).Resume(parseResume(t, `{"name":"一二三四五六七八九十一","skills":[""]}`))
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("err = %v", err)
+	}
+
+	tests := []struct {
+		lang string
+		want []string
+	}{
+		{LangZH, []string{"name: 长度不能超过 10 个字符", "skills[0]: 不能为空"}},
+		{LangEN, []string{"name: must be at most 10 characters", "skills[0]: is required"}},
+		{"fr", []string{"name: 长度不能超过 10 个字符", "skills[0]: 不能为空"}},
+	}
+	for _, tt := range tests {
+		localized := Localize(verr, tt.lang)
+		var got []string
+		for _, f := range localized.Fields {
+			got = append(got, f.String())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Localize(%s) = %q，期望 %q", tt.lang, got, tt.want)
+		}
+	}
+	if verr.Fields[0].Message != "长度不能超过 10 个字符" {
+		t.Errorf("Localize 修改了原错误: %q", verr.Fields[0].Message)
+	}
+}
+
+func TestParseLang(t *testing.T) {
+	tests := map[string]string{
+		"":                        LangZH,
+		"zh-CN,zh;q=0.9,en;q=0.8": LangZH,
+		"en-US,en;q=0.9":          LangEN,
+		" EN ;q=1":                LangEN,
+		"fr-FR":                   LangZH,
+	}
+	for in, want := range tests {
+		if got := ParseLang(in); got != want {
+			t.Errorf("ParseLang(%q) = %q，期望 %q", in, got, want)
+		}
+	}
+}
+
+func TestChanges(t *testing.T) {
+	// 升级前保存的简历：日期无法识别，且内容超出了当前的长度限制
+	legacy := `{"name":"默认简历","summary":"` + strings.Repeat("长", 21) + `",
+		"experience":[{"id":"x1","company":"字节跳动","start_date":"2019.09-2020.06"},{"id":"x2","company":"腾讯","start_date":"2018"}],
+		"skills":["Go",""]}`
+
+	tests := []struct {
+		name   string
+		change func(r *domain.Resume)
+		want   []string
+	}{
+		{"未修改", func(*domain.Resume) {}, nil},
+		{"修改其他条目", func(r *domain.Resume) { r.Experience[1].Position = "实习生" }, nil},
+		{"调整条目顺序", func(r *domain.Resume) { r.Experience[0], r.Experience[1] = r.Experience[1], r.Experience[0] }, nil},
+		{"新增有效条目", func(r *domain.Resume) { r.Awards = append(r.Awards, domain.Award{Title: "优秀员工"}) }, nil},
+		{
+			"新增无效条目",
+			func(r *domain.Resume) {
+				r.Awards = append(r.Awards, domain.Award{Title: "优秀员工"}, domain.Award{})
+			},
+			[]string{"awards[1].title required"},
+		},
+		{
+			"修改有问题的条目",
+			func(r *domain.Resume) { r.Experience[0].Position = "后端工程师" },
+			[]string{"experience[0].start_date date"},
+		},
+		{
+			"新增条目超出条数限制",
+			func(r *domain.Resume) {
+				r.Experience = append(r.Experience, domain.Experience{Company: "阿里"})
+				r.Name = strings.Repeat("长", 11)
+			},
+			[]string{"name max_length", "experience max_items"},
+		},
+		{"修改简介", func(r *domain.Resume) { r.Summary += "。" }, []string{"summary max_length"}},
+		{"新增技能", func(r *domain.Resume) { r.Skills = append(r.Skills, "Redis") }, nil},
+	}
+	v := New(testConfig)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := parseResume(t, legacy)
+			after := before.Clone()
+			tt.change(after)
+			if got := problems(t, v.Changes(before, after)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changes = %q\n期望 %q", got, tt.want)
+			}
+		})
+	}
+
+	// 没有修改前的版本时校验整份简历
+	if got := problems(t, v.Changes(nil, parseResume(t, legacy))); len(got) != 3 {
+		t.Errorf("Changes(nil, r) = %q", got)
+	}
+}
@@ -597,6 +597,29 @@ function renderResume(resume) {
         </div>`;
     }
 
+    // 自定义章节
+    (resume.custom_sections || []).forEach(section => {
+        if (!section.title || !section.entries || section.entries.length === 0) return;
+        html += `<div class="resume-section"><h3>📌 ${section.title}</h3>`;
+        section.entries.forEach(entry => {
+            html += `<div class="project-item">
+                <div class="item-header">
+                    <div>
+                        <div class="item-title">${entry.url ? `<a href="${entry.url}" target="_blank">${entry.title || ''}</a>` : (entry.title || '')}</div>
+                        ${entry.subtitle ? `<div class="item-subtitle">${entry.subtitle}</div>` : ''}
+                    </div>
+                    ${entry.start_date || entry.end_date ? `<div class="item-date">${entry.start_date || ''} - ${entry.end_date || ''}</div>` : ''}
+                </div>
+                ${entry.bullets && entry.bullets.length > 0 ? `
+                    <ul class="item-list">
+                        ${entry.bullets.map(b => `<li>${b}</li>`).join('')}
+                    </ul>
+                ` : ''}
+            </div>`;
+        });
+        html += `</div>`;
+    });
+
     document.getElementById('resumeContent').innerHTML = html;
     document.getElementById('resumeDisplay').style.display = 'block';
 }
@@ -5,6 +5,7 @@ const API_BASE_URL = 'http://localhost:8080/api';
 // 全局变量
 let currentUserID = '';
 let currentResume = null;
+let currentVersion = 0; // 简历版本，保存时作为 If-Match 提交，避免覆盖其他页面的修改
 let educationCount = 0;
 let experienceCount = 0;
 let projectCount = 0;
@@ -40,11 +41,11 @@ document.addEventListener('DOMContentLoaded', () => {
 async function apiRequest(url, options = {}) {
     try {
         const response = await fetch(url, {
+            ...options,
             headers: {
                 'Content-Type': 'application/json',
                 ...options.headers,
             },
-            ...options,
         });
 
         const contentType = response.headers.get('content-type');
@@ -170,6 +171,7 @@ async function loadResume() {
         resume = cleanResumeData(resume);
 
         currentResume = resume;
+        currentVersion = resume.version || 0;
 
         // 填充表单
         fillForm(resume);
@@ -469,6 +471,14 @@ function collectFormData() {
         projects: []
     };
 
+    // 编辑页暂不提供表单的章节原样保留，避免保存时被清空
+    const preserved = ['summary', 'certifications', 'awards', 'languages', 'links', 'publications', 'custom_sections'];
+    preserved.forEach(key => {
+        if (currentResume && currentResume[key] !== undefined) {
+            data[key] = currentResume[key];
+        }
+    });
+
     // 基本信息
     const name = document.getElementById('name').value.trim();
     const email = document.getElementById('email').value.trim();
@@ -725,12 +735,14 @@ async function saveResume() {
     showLoading(true);
     try {
         // 直接保存简历数据，不需要 AI 解析
-        await apiRequest(`${API_BASE_URL}/resume`, {
+        const result = await apiRequest(`${API_BASE_URL}/resume`, {
             method: 'POST',
+            headers: currentVersion ? { 'If-Match': `"${currentVersion}"` } : {},
             body: JSON.stringify(data),
         });
 
         currentResume = data;
+        currentVersion = result?.version || 0;
         changesMade = false;
         showToast('✅ 保存成功', 'success');
     } catch (error) {
@@ -848,6 +860,8 @@ async function addGitHubProject() {
 
         // 阶段2：AI分析完成
         showToast('🤖 AI分析完成，正在添加到简历...', 'info');
+        // 项目已由服务端写入简历，后续保存以新版本为准
+        currentVersion = response.version || currentVersion;
 
         // 从返回的简历数据中提取最新添加的项目
         if (response.projects && response.projects.length > 0) {
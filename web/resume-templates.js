@@ -68,6 +68,9 @@ const ResumeTemplates = {
             }
         }
 
+        // 个人简介
+        html += this._renderSummary(resume);
+
         // 教育背景
         html += this._renderSection(resume.education, 'education', '教育背景', (edu) => `
             <div class="classic-item">
@@ -122,6 +125,12 @@ const ResumeTemplates = {
             </div>
         `);
 
+        // 证书、奖项、语言、链接、出版物
+        html += this._renderExtraSections(resume);
+
+        // 自定义章节
+        html += this._renderCustomSections(resume);
+
         html += '</div>';
         return html;
     },
@@ -168,6 +177,9 @@ const ResumeTemplates = {
         // 右侧主内容
         html += '<div class="modern-main">';
 
+        // 个人简介
+        html += this._renderSummary(resume, 'modern-section');
+
         // 教育背景
         html += this._renderSection(resume.education, 'education', '教育背景', (edu) => `
             <div class="modern-item">
@@ -211,6 +223,12 @@ const ResumeTemplates = {
             </div>
         `, 'modern-section');
 
+        // 证书、奖项、语言、链接、出版物
+        html += this._renderExtraSections(resume, 'modern-section');
+
+        // 自定义章节
+        html += this._renderCustomSections(resume, 'modern-section');
+
         html += '</div>'; // 右侧主内容结束
         html += '</div>'; // modern容器结束
         return html;
@@ -235,6 +253,9 @@ const ResumeTemplates = {
             </div>
         `;
 
+        // 个人简介
+        html += this._renderSummary(resume, 'minimal-section');
+
         // 教育背景
         html += this._renderSection(resume.education, 'education', '教育背景', (edu) => `
             <div class="minimal-item">
@@ -284,6 +305,12 @@ const ResumeTemplates = {
             </div>
         `, 'minimal-section');
 
+        // 证书、奖项、语言、链接、出版物
+        html += this._renderExtraSections(resume, 'minimal-section');
+
+        // 自定义章节
+        html += this._renderCustomSections(resume, 'minimal-section');
+
         html += '</div>';
         return html;
     },
@@ -300,6 +327,17 @@ const ResumeTemplates = {
                     return item.company || item.position;
                 case 'projects':
                     return item.name || item.description;
+                case 'certifications':
+                    return item.name;
+                case 'awards':
+                case 'publications':
+                    return item.title;
+                case 'languages':
+                    return item.language;
+                case 'links':
+                    return item.url;
+                case 'custom':
+                    return item.title;
                 default:
                     return true;
             }
@@ -318,6 +356,95 @@ const ResumeTemplates = {
         `;
     },
 
+    // 个人简介，各模板共用
+    _renderSummary(resume, sectionClass = '') {
+        if (!resume.summary) return '';
+
+        const t = this.currentTemplate;
+        return `
+            <div class="${sectionClass || `${t}-section`}">
+                <h2 class="${t}-section-title">个人简介</h2>
+                <div class="${t}-desc">${resume.summary}</div>
+            </div>
+        `;
+    },
+
+    // 证书、奖项、语言、链接、出版物，各模板共用，样式沿用模板的条目样式
+    _renderExtraSections(resume, sectionClass = '') {
+        const t = this.currentTemplate;
+        let html = '';
+
+        html += this._renderSection(resume.certifications, 'certifications', '证书资质', (cert) => `
+            <div class="${t}-item">
+                <div class="${t}-item-header">
+                    <strong>${cert.url ? `<a href="${cert.url}" target="_blank">${cert.name}</a>` : cert.name}</strong>
+                    <span class="${t}-date">${this._formatDate(cert.date)}</span>
+                </div>
+                ${cert.issuer ? `<div class="${t}-desc">${cert.issuer}</div>` : ''}
+            </div>
+        `, sectionClass);
+
+        html += this._renderSection(resume.awards, 'awards', '获奖经历', (award) => `
+            <div class="${t}-item">
+                <div class="${t}-item-header">
+                    <strong>${award.title}</strong>
+                    <span class="${t}-date">${this._formatDate(award.date)}</span>
+                </div>
+                ${award.issuer ? `<div class="${t}-desc">${award.issuer}</div>` : ''}
+                ${award.description ? `<div class="${t}-desc">${award.description}</div>` : ''}
+            </div>
+        `, sectionClass);
+
+        html += this._renderSection(resume.languages, 'languages', '语言能力', (lang) => `
+            <div class="${t}-item">
+                <strong>${lang.language}</strong>${lang.proficiency ? ` · ${lang.proficiency}` : ''}
+            </div>
+        `, sectionClass);
+
+        html += this._renderSection(resume.links, 'links', '个人链接', (link) => `
+            <div class="${t}-item">
+                ${link.label ? `${link.label}: ` : ''}<a href="${link.url}" target="_blank">${link.url}</a>
+            </div>
+        `, sectionClass);
+
+        html += this._renderSection(resume.publications, 'publications', '论文著作', (pub) => `
+            <div class="${t}-item">
+                <div class="${t}-item-header">
+                    <strong>${pub.url ? `<a href="${pub.url}" target="_blank">${pub.title}</a>` : pub.title}</strong>
+                    <span class="${t}-date">${this._formatDate(pub.date)}</span>
+                </div>
+                ${pub.publisher ? `<div class="${t}-desc">${pub.publisher}</div>` : ''}
+                ${pub.description ? `<div class="${t}-desc">${pub.description}</div>` : ''}
+            </div>
+        `, sectionClass);
+
+        return html;
+    },
+
+    // 自定义章节，按用户设定的顺序和标题渲染
+    _renderCustomSections(resume, sectionClass = '') {
+        if (!resume.custom_sections || resume.custom_sections.length === 0) return '';
+
+        const t = this.currentTemplate;
+        return resume.custom_sections
+            .filter(section => section.title)
+            .map(section => this._renderSection(section.entries, 'custom', section.title, (entry) => `
+                <div class="${t}-item">
+                    <div class="${t}-item-header">
+                        <strong>${entry.url ? `<a href="${entry.url}" target="_blank">${entry.title}</a>` : entry.title}</strong>
+                        <span class="${t}-date">${this._formatDateRange(entry.start_date, entry.end_date)}</span>
+                    </div>
+                    ${entry.subtitle ? `<div class="${t}-desc">${entry.subtitle}</div>` : ''}
+                    ${entry.bullets && entry.bullets.length > 0 ? `
+                        <ul class="${t}-highlights">
+                            ${entry.bullets.map(b => `<li>${b}</li>`).join('')}
+                        </ul>
+                    ` : ''}
+                </div>
+            `, sectionClass))
+            .join('');
+    },
+
     _formatDateRange(start, end) {
         if (!start && !end) return '';
         if (start && end) {